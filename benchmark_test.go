@@ -1,7 +1,9 @@
 package kong
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"strconv"
 	"testing"
 
@@ -77,3 +79,131 @@ func Benchmark_interpolateValue(b *testing.B) {
 	}
 	b.ReportAllocs()
 }
+
+// A resolver simulating an expensive source, where each lookup has a fixed cost.
+type expensiveResolver struct {
+	data []byte
+}
+
+func (e *expensiveResolver) load() (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	return values, json.Unmarshal(e.data, &values)
+}
+
+func (e *expensiveResolver) Validate(app *Application) error { return nil }
+
+func (e *expensiveResolver) Resolve(context *Context, parent *Path, flag *Flag) (interface{}, error) {
+	values, err := e.load()
+	if err != nil {
+		return nil, err
+	}
+	return values[flag.Name], nil
+}
+
+type expensiveBatchResolver struct {
+	expensiveResolver
+}
+
+func (e *expensiveBatchResolver) ResolveAll(context *Context, parent *Path, flags []*Flag) (map[*Flag]interface{}, error) {
+	values, err := e.load()
+	if err != nil {
+		return nil, err
+	}
+	out := map[*Flag]interface{}{}
+	for _, flag := range flags {
+		if value, ok := values[flag.Name]; ok {
+			out[flag] = value
+		}
+	}
+	return out, nil
+}
+
+func BenchmarkResolve(b *testing.B) {
+	const count = 100
+	fields := []reflect.StructField{}
+	values := map[string]interface{}{}
+	for i := 0; i < count; i++ {
+		fields = append(fields, reflect.StructField{Name: fmt.Sprintf("Flag%d", i), Type: reflect.TypeOf("")})
+		values[fmt.Sprintf("flag-%d", i)] = strconv.Itoa(i)
+	}
+	data, err := json.Marshal(values)
+	require.NoError(b, err)
+	grammar := reflect.New(reflect.StructOf(fields)).Interface()
+
+	for _, test := range []struct {
+		name     string
+		resolver Resolver
+	}{
+		{"PerFlag", &expensiveResolver{data: data}},
+		{"Batch", &expensiveBatchResolver{expensiveResolver{data: data}}},
+	} {
+		test := test
+		b.Run(test.name, func(b *testing.B) {
+			k, err := New(grammar, Resolvers(test.resolver))
+			require.NoError(b, err)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err = k.Parse(nil)
+			}
+			require.NoError(b, err)
+		})
+	}
+}
+
+func BenchmarkLargeArgs(b *testing.B) {
+	var cli struct {
+		Verbose bool     `short:"v"`
+		Item    []string `sep:"none"`
+		Files   []string `arg:"" optional:""`
+	}
+	for _, count := range []int{1000, 10000, 100000} {
+		positionals := make([]string, count)
+		flags := make([]string, count)
+		shorts := make([]string, count)
+		for i := 0; i < count; i++ {
+			positionals[i] = fmt.Sprintf("file%d", i)
+			flags[i] = fmt.Sprintf("--item=%d", i)
+			shorts[i] = "-v"
+		}
+		for _, test := range []struct {
+			name string
+			args []string
+		}{
+			{"Positionals", positionals},
+			{"Flags", flags},
+			{"ShortFlags", shorts},
+		} {
+			test := test
+			b.Run(fmt.Sprintf("%s/%d", test.name, count), func(b *testing.B) {
+				k, err := New(&cli)
+				require.NoError(b, err)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					_, err = k.Parse(test.args)
+				}
+				require.NoError(b, err)
+			})
+		}
+	}
+}
+
+func BenchmarkScanner(b *testing.B) {
+	args := make([]string, 100000)
+	for i := range args {
+		args[i] = fmt.Sprintf("--flag%d=value", i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s := Scan(args...)
+		// Simulate the parser splitting each token and pushing the parts back.
+		for token := s.Pop(); !token.IsEOL(); token = s.Pop() {
+			if token.Type == UntypedToken {
+				s.PushTyped("value", FlagValueToken)
+				s.PushTyped("flag", FlagToken)
+			}
+		}
+	}
+}
@@ -24,24 +24,132 @@ func build(k *Kong, ast interface{}) (app *Application, err error) {
 	if len(node.Positional) > 0 && len(node.Children) > 0 {
 		return nil, fmt.Errorf("can't mix positional arguments and branching arguments on %T", ast)
 	}
+	// Short names declared by the grammar take precedence over those of builtin flags, eg. -h for help.
+	for _, flag := range extraFlags {
+		if flag.Short != 0 && declaresShort(node, flag.Short) {
+			flag.Short = 0
+		}
+	}
 	app.Node = node
 	app.Node.Flags = append(extraFlags, app.Node.Flags...)
 	app.Tag = newEmptyTag()
 	app.Tag.Vars = k.vars
+	if k.helpCommand {
+		app.HelpCommand = buildHelpCommand(k, app.Node, seenFlags)
+	}
+	checkRequiredFor(app.Node)
 	return app, nil
 }
 
+// Check that "requiredfor" tags and RequiredFlagsProvider implementations refer to existing commands and flags.
+func checkRequiredFor(app *Node) {
+	commands := map[string]bool{}
+	_ = Visit(app, func(node Visitable, next Next) error {
+		if n, ok := node.(*Node); ok && n.Type == CommandNode {
+			commands[n.Path()] = true
+		}
+		return next(nil)
+	})
+	_ = Visit(app, func(node Visitable, next Next) error {
+		switch node := node.(type) {
+		case *Flag:
+			for _, path := range node.Tag.RequiredFor {
+				if !commands[path] {
+					fail("%s: requiredfor refers to unknown command %q", node.ShortSummary(), path)
+				}
+			}
+		case *Node:
+		outer:
+			for _, name := range node.RequiredFlags {
+				for _, group := range node.AllFlags(false) {
+					for _, flag := range group {
+						if flag.Name == name {
+							continue outer
+						}
+					}
+				}
+				fail("%s: RequiredFlags() refers to unknown flag --%s", node.Name, name)
+			}
+		}
+		return next(nil)
+	})
+}
+
+func buildHelpCommand(k *Kong, node *Node, seenFlags map[string]bool) *Command {
+	if len(node.Positional) > 0 {
+		fail("can't add a help command to an application with positional arguments")
+	}
+	for _, child := range node.Children {
+		if child.Type == CommandNode && child.Name == "help" {
+			fail("duplicate command help")
+		}
+	}
+	tag := newEmptyTag()
+	tag.Cmd = true
+	tag.Help = "Show help for a command."
+	fv := reflect.ValueOf(&helpCommand{}).Elem()
+	buildChild(k, node, CommandNode, node.Target, reflect.StructField{Name: "Help"}, fv, tag, "help", seenFlags)
+	return node.Children[len(node.Children)-1]
+}
+
+// Returns true if a flag in the tree under node has the given short name.
+func declaresShort(node *Node, short rune) bool {
+	found := false
+	_ = Visit(node, func(node Visitable, next Next) error {
+		if flag, ok := node.(*Flag); ok && flag.Short == short {
+			found = true
+		}
+		return next(nil)
+	})
+	return found
+}
+
 func dashedString(s string) string {
 	return strings.Join(camelCase(s), "-")
 }
 
 type flattenedField struct {
-	field reflect.StructField
-	value reflect.Value
-	tag   *Tag
+	field      reflect.StructField
+	value      reflect.Value
+	tag        *Tag
+	path       string // Dot-separated field path, including the names of enclosing embedded struct fields.
+	takesValue bool   // See takesValue().
+	embedded   bool   // True if the field is from an embedded struct, so its tag may have inherited keys.
+}
+
+// Tag inheritance policies for the fields of embedded structs.
+type fieldInheritance struct {
+	policies      map[string]TagInheritance
+	noPlaceholder map[string]TagInheritance // Policies without "placeholder", created on first use.
 }
 
-func flattenedFields(v reflect.Value) (out []flattenedField) {
+func (f *fieldInheritance) policiesFor(field flattenedField) map[string]TagInheritance {
+	// Placeholders only make sense for flags that take a value.
+	if _, ok := f.policies["placeholder"]; !ok || field.takesValue {
+		return f.policies
+	}
+	if f.noPlaceholder == nil {
+		f.noPlaceholder = map[string]TagInheritance{}
+		for key, policy := range f.policies {
+			f.noPlaceholder[key] = policy
+		}
+		delete(f.noPlaceholder, "placeholder")
+	}
+	return f.noPlaceholder
+}
+
+func flattenedFields(k *Kong, v reflect.Value) []flattenedField {
+	out := flattenFields(k, v, &fieldInheritance{policies: k.tagInheritance()})
+	// Tags are hydrated once all inherited keys have been applied.
+	for _, field := range out {
+		if field.embedded {
+			field.tag.hydrate(field.value)
+		}
+	}
+	return out
+}
+
+func flattenFields(k *Kong, v reflect.Value, inheritance *fieldInheritance) (out []flattenedField) {
 	v = reflect.Indirect(v)
 	for i := 0; i < v.NumField(); i++ {
 		ft := v.Type().Field(i)
@@ -54,16 +162,13 @@ func flattenedFields(v reflect.Value) (out []flattenedField) {
 			if fv.Kind() == reflect.Interface {
 				fv = fv.Elem()
 			}
-			sub := flattenedFields(fv)
-			for _, subf := range sub {
-				// Assign parent if it's not already set.
-				if subf.tag.Group == "" {
-					subf.tag.Group = tag.Group
+			sub := flattenFields(k, fv, inheritance)
+			for i, subf := range sub {
+				subf.tag.inherit(tag, inheritance.policiesFor(subf))
+				sub[i].embedded = true
+				if !ft.Anonymous {
+					sub[i].path = ft.Name + "." + subf.path
 				}
-				// Accumulate prefixes.
-				subf.tag.Prefix = tag.Prefix + subf.tag.Prefix
-				// Combine parent vars.
-				subf.tag.Vars = tag.Vars.CloneWith(subf.tag.Vars)
 			}
 			out = append(out, sub...)
 			continue
@@ -71,18 +176,30 @@ func flattenedFields(v reflect.Value) (out []flattenedField) {
 		if !fv.CanSet() {
 			continue
 		}
-		out = append(out, flattenedField{field: ft, value: fv, tag: tag})
+		out = append(out, flattenedField{field: ft, value: fv, tag: tag, path: ft.Name, takesValue: takesValue(k, fv, tag)})
 	}
 	return out
 }
 
+// Returns true if a field is a flag that takes a value, ie. not a command, positional argument or boolean flag.
+func takesValue(k *Kong, fv reflect.Value, tag *Tag) bool {
+	if tag.Cmd || tag.Arg {
+		return false
+	}
+	mapper := k.registry.ForNamedValue(tag.Type, fv)
+	if m, ok := mapper.(BoolMapper); ok && m.IsBool() {
+		return false
+	}
+	return fv.Kind() != reflect.Bool
+}
+
 func buildNode(k *Kong, v reflect.Value, typ NodeType, seenFlags map[string]bool) *Node {
 	node := &Node{
 		Type:   typ,
 		Target: v,
 		Tag:    newEmptyTag(),
 	}
-	for _, field := range flattenedFields(v) {
+	for _, field := range flattenedFields(k, v) {
 		ft := field.field
 		fv := field.value
 
@@ -102,8 +219,19 @@ func buildNode(k *Kong, v reflect.Value, typ NodeType, seenFlags map[string]bool
 			}
 			buildChild(k, node, typ, v, ft, fv, tag, name, seenFlags)
 		} else {
-			buildField(k, node, v, ft, fv, tag, name, seenFlags)
+			buildField(k, node, v, ft, fv, tag, name, field.path, seenFlags)
+		}
+	}
+
+	shorts := map[rune]bool{}
+	for _, flag := range node.Flags {
+		if flag.Short == 0 {
+			continue
+		}
+		if shorts[flag.Short] {
+			fail("duplicate short flag -%c", flag.Short)
 		}
+		shorts[flag.Short] = true
 	}
 
 	// "Unsee" flags.
@@ -132,10 +260,16 @@ func buildChild(k *Kong, node *Node, typ NodeType, v reflect.Value, ft reflect.S
 	child.Help = tag.Help
 	child.Hidden = tag.Hidden
 	child.Group = tag.Group
+	if len(tag.ConfigFiles) > 0 && k.loader == nil {
+		fail("%s: configfiles requires kong.Configuration(...)", name)
+	}
 
 	if provider, ok := fv.Addr().Interface().(HelpProvider); ok {
 		child.Detail = provider.Help()
 	}
+	if provider, ok := fv.Addr().Interface().(RequiredFlagsProvider); ok {
+		child.RequiredFlags = provider.RequiredFlags()
+	}
 
 	// A branching argument. This is a bit hairy, as we let buildNode() do the parsing, then check that
 	// a positional argument is provided to the child, and move it to the branching argument field.
@@ -168,14 +302,41 @@ func buildChild(k *Kong, node *Node, typ NodeType, v reflect.Value, ft reflect.S
 	}
 }
 
-func buildField(k *Kong, node *Node, v reflect.Value, ft reflect.StructField, fv reflect.Value, tag *Tag, name string, seenFlags map[string]bool) {
+// Check that a field with an "arity" tag can hold that many values.
+func checkArity(v reflect.Value, ft reflect.StructField, arity int) {
+	switch ft.Type.Kind() {
+	case reflect.Slice:
+	case reflect.Array:
+		if ft.Type.Len() != arity {
+			fail("%s.%s has arity %d but is an array of length %d", v.Type(), ft.Name, arity, ft.Type.Len())
+		}
+	case reflect.Struct:
+		if ft.Type.NumField() != arity {
+			fail("%s.%s has arity %d but has %d fields", v.Type(), ft.Name, arity, ft.Type.NumField())
+		}
+		for i := 0; i < ft.Type.NumField(); i++ {
+			if ft.Type.Field(i).PkgPath != "" {
+				fail("%s.%s has arity but unexported field %s", v.Type(), ft.Name, ft.Type.Field(i).Name)
+			}
+		}
+	default:
+		fail("%s.%s has arity but is not an array, slice or struct", v.Type(), ft.Name)
+	}
+}
+
+func buildField(k *Kong, node *Node, v reflect.Value, ft reflect.StructField, fv reflect.Value, tag *Tag, name, path string, seenFlags map[string]bool) {
 	mapper := k.registry.ForNamedValue(tag.Type, fv)
+	if tag.Arity > 0 {
+		checkArity(v, ft, tag.Arity)
+		mapper = arityDecoder(k.registry)
+	}
 	if mapper == nil {
 		fail("unsupported field type %s.%s (of type %s)", v.Type(), ft.Name, ft.Type)
 	}
 
 	value := &Value{
 		Name:         name,
+		Field:        path,
 		Help:         tag.Help,
 		Default:      tag.Default,
 		DefaultValue: reflect.New(fv.Type()).Elem(),
@@ -191,21 +352,31 @@ func buildField(k *Kong, node *Node, v reflect.Value, ft reflect.StructField, fv
 
 	if tag.Arg {
 		node.Positional = append(node.Positional, value)
-	} else {
-		if seenFlags[value.Name] {
-			fail("duplicate flag --%s", value.Name)
-		}
-		seenFlags[value.Name] = true
-		flag := &Flag{
-			Value:       value,
-			Short:       tag.Short,
-			PlaceHolder: tag.PlaceHolder,
-			Env:         tag.Env,
-			Group:       tag.Group,
-			Xor:         tag.Xor,
-			Hidden:      tag.Hidden,
-		}
-		value.Flag = flag
-		node.Flags = append(node.Flags, flag)
+		if !tag.Flag {
+			return
+		}
+		// The field may also be set with a flag, which shares its target with the positional argument.
+		positional := value
+		value = &Value{}
+		*value = *positional
+		value.Required = false
+		value.Paired = positional
+		positional.Paired = value
+	}
+	if seenFlags[value.Name] {
+		fail("duplicate flag --%s", value.Name)
+	}
+	seenFlags[value.Name] = true
+	flag := &Flag{
+		Value:       value,
+		Short:       tag.Short,
+		ShortOnly:   tag.ShortOnly,
+		PlaceHolder: tag.PlaceHolder,
+		Env:         tag.Env,
+		Group:       tag.Group,
+		Xor:         tag.Xor,
+		Hidden:      tag.Hidden,
 	}
+	value.Flag = flag
+	node.Flags = append(node.Flags, flag)
 }
@@ -0,0 +1,323 @@
+// Command kong-getopt parses shell script arguments with Kong, driven by a JSON CLI spec.
+//
+// It prints shell-safe variable assignments for the parsed values, suitable for eval:
+//
+//	eval "$(kong-getopt deploy.json -- "$@")"
+//
+// Help and errors are written to stderr, and an "exit N" statement is printed so that the script terminates with the
+// appropriate status.
+//
+// The output is for bash: list values are assigned as bash arrays, which POSIX sh does not support.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+
+	"github.com/alecthomas/kong"
+)
+
+// Exit code used for parse errors, as with getopt(1).
+const usageExitCode = 2
+
+// Spec describes a command-line interface.
+type Spec struct {
+	Name        string       `json:"name"`
+	Help        string       `json:"help"`
+	Description string       `json:"description"`
+	Flags       []*ValueSpec `json:"flags"`
+	Args        []*ValueSpec `json:"args"`
+	Commands    []*Spec      `json:"commands"`
+	Hidden      bool         `json:"hidden"`
+}
+
+// ValueSpec describes a flag or positional argument.
+type ValueSpec struct {
+	Name        string `json:"name"`
+	Help        string `json:"help"`
+	Type        string `json:"type"` // One of string (default), bool, int, float, duration, path or list.
+	Short       string `json:"short"`
+	Default     string `json:"default"`
+	Enum        string `json:"enum"`
+	Env         string `json:"env"`
+	PlaceHolder string `json:"placeholder"`
+	Required    bool   `json:"required"`
+	Optional    bool   `json:"optional"`
+	Hidden      bool   `json:"hidden"`
+}
+
+var cli struct {
+	Prefix string   `help:"Prefix for variable names."`
+	Spec   string   `arg:"" help:"JSON CLI spec." type:"existingfile"`
+	Args   []string `arg:"" optional:"" help:"Arguments to parse, following --."`
+}
+
+func main() {
+	ctx := kong.Parse(&cli,
+		kong.Name("kong-getopt"),
+		kong.Description("Parse shell script arguments with Kong, printing variable assignments for eval by bash."),
+		kong.UsageOnError())
+	r, err := os.Open(cli.Spec)
+	ctx.FatalIfErrorf(err)
+	status := run(r, cli.Prefix, cli.Args, os.Stdout, os.Stderr)
+	_ = r.Close()
+	os.Exit(status)
+}
+
+// Parse args according to the JSON spec in r, writing shell assignments to stdout and help or errors to stderr.
+//
+// Returns the exit status.
+func run(r io.Reader, prefix string, args []string, stdout, stderr io.Writer) int {
+	invalidSpec := func(err error) int {
+		fmt.Fprintf(stderr, "kong-getopt: error: invalid spec: %s\n", err)
+		fmt.Fprintln(stdout, "exit 1")
+		return 1
+	}
+	spec := &Spec{}
+	if err := json.NewDecoder(r).Decode(spec); err != nil {
+		return invalidSpec(err)
+	}
+	grammar, err := buildGrammar(spec)
+	if err != nil {
+		return invalidSpec(err)
+	}
+
+	// Kong exits when help is requested, so unwind the stack instead and report the status via "exit N".
+	type exited struct{ status int }
+	options := []kong.Option{
+		kong.Description(spec.Description),
+		kong.Writers(stderr, stderr),
+		kong.Exit(func(status int) { panic(exited{status}) }),
+	}
+	if spec.Name != "" {
+		options = append(options, kong.Name(spec.Name))
+	}
+	parser, err := kong.New(grammar, options...)
+	if err != nil {
+		return invalidSpec(err)
+	}
+	status := -1
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				e, ok := r.(exited)
+				if !ok {
+					panic(r)
+				}
+				status = e.status
+			}
+		}()
+		ctx, err := parser.Parse(args)
+		if err != nil {
+			parser.Errorf("%s", err)
+			status = usageExitCode
+			return
+		}
+		writeAssignments(stdout, prefix, ctx)
+	}()
+	if status >= 0 {
+		fmt.Fprintf(stdout, "exit %d\n", status)
+		return status
+	}
+	return 0
+}
+
+// Build a pointer to a new struct value with Kong tags from spec.
+func buildGrammar(spec *Spec) (interface{}, error) {
+	typ, err := buildStruct(spec, map[string]string{"COMMAND": "the selected command"})
+	if err != nil {
+		return nil, err
+	}
+	return reflect.New(typ).Interface(), nil
+}
+
+// Build a struct type for spec.
+//
+// "variables" maps the shell variable names already assigned by parent commands to their flag or argument names.
+func buildStruct(spec *Spec, variables map[string]string) (reflect.Type, error) {
+	fields := []reflect.StructField{}
+	// Field names are only used internally, so just need to be unique exported identifiers.
+	add := func(typ reflect.Type, tag tags) {
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("Field%d", len(fields)),
+			Type: typ,
+			Tag:  tag.StructTag(),
+		})
+	}
+	variables = copyVariables(variables)
+	for _, flag := range spec.Flags {
+		typ, tag, err := buildValue(flag, variables)
+		if err != nil {
+			return nil, err
+		}
+		add(typ, tag)
+	}
+	for _, arg := range spec.Args {
+		typ, tag, err := buildValue(arg, variables)
+		if err != nil {
+			return nil, err
+		}
+		tag = append(tag, "arg")
+		add(typ, tag)
+	}
+	for _, cmd := range spec.Commands {
+		if cmd.Name == "" {
+			return nil, errors.New("command is missing a name")
+		}
+		typ, err := buildStruct(cmd, variables)
+		if err != nil {
+			return nil, errors.Wrap(err, cmd.Name)
+		}
+		tag := tags{"cmd"}.with("name", cmd.Name).with("help", cmd.Help)
+		if cmd.Hidden {
+			tag = append(tag, "hidden")
+		}
+		add(typ, tag)
+	}
+	return reflect.StructOf(fields), nil
+}
+
+var valueTypes = map[string]struct {
+	typ     reflect.Type
+	kongTyp string
+}{
+	"":         {reflect.TypeOf(""), ""},
+	"string":   {reflect.TypeOf(""), ""},
+	"bool":     {reflect.TypeOf(false), ""},
+	"int":      {reflect.TypeOf(0), ""},
+	"float":    {reflect.TypeOf(0.0), ""},
+	"duration": {reflect.TypeOf(time.Duration(0)), ""},
+	"path":     {reflect.TypeOf(""), "path"},
+	"list":     {reflect.TypeOf([]string{}), ""},
+}
+
+func buildValue(spec *ValueSpec, variables map[string]string) (reflect.Type, tags, error) {
+	if spec.Name == "" {
+		return nil, nil, errors.New("flag or argument is missing a name")
+	}
+	variable := variableName(spec.Name)
+	if unicode.IsDigit(rune(variable[0])) {
+		return nil, nil, errors.Errorf("%s: name must not start with a digit", spec.Name)
+	}
+	if other, ok := variables[variable]; ok {
+		return nil, nil, errors.Errorf("%s: variable %s is already used by %s", spec.Name, variable, other)
+	}
+	variables[variable] = spec.Name
+	valueType, ok := valueTypes[spec.Type]
+	if !ok {
+		return nil, nil, errors.Errorf("%s: unsupported type %q", spec.Name, spec.Type)
+	}
+	tag := tags{}.
+		with("name", spec.Name).
+		with("help", spec.Help).
+		with("type", valueType.kongTyp).
+		with("short", spec.Short).
+		with("default", spec.Default).
+		with("enum", spec.Enum).
+		with("env", spec.Env).
+		with("placeholder", spec.PlaceHolder)
+	if spec.Required {
+		tag = append(tag, "required")
+	}
+	if spec.Optional {
+		tag = append(tag, "optional")
+	}
+	if spec.Hidden {
+		tag = append(tag, "hidden")
+	}
+	return valueType.typ, tag, nil
+}
+
+func copyVariables(variables map[string]string) map[string]string {
+	out := make(map[string]string, len(variables))
+	for k, v := range variables {
+		out[k] = v
+	}
+	return out
+}
+
+// Items in a Kong struct tag, eg. "required" or "name='foo'".
+type tags []string
+
+// Add key='value' to the tags if value is not empty.
+func (t tags) with(key, value string) tags {
+	if value == "" {
+		return t
+	}
+	return append(t, fmt.Sprintf("%s='%s'", key, strings.Replace(value, "'", `\'`, -1)))
+}
+
+func (t tags) StructTag() reflect.StructTag {
+	return reflect.StructTag("kong:" + strconv.Quote(strings.Join(t, ",")))
+}
+
+// Write shell variable assignments for the selected command and the flags and arguments in ctx.
+func writeAssignments(w io.Writer, prefix string, ctx *kong.Context) {
+	assign := func(name, value string) {
+		fmt.Fprintf(w, "%s%s=%s\n", prefix, variableName(name), value)
+	}
+	command := []string{}
+	values := []*kong.Value{}
+	for _, path := range ctx.Path {
+		var node *kong.Node
+		switch {
+		case path.App != nil:
+			node = path.App.Node
+		case path.Command != nil:
+			node = path.Command
+			command = append(command, node.Name)
+		default:
+			continue
+		}
+		for _, flag := range node.Flags {
+			if flag != ctx.Model.HelpFlag {
+				values = append(values, flag.Value)
+			}
+		}
+		values = append(values, node.Positional...)
+	}
+	assign("command", shellQuote(strings.Join(command, " ")))
+	for _, value := range values {
+		assign(value.Name, shellValue(value.Target))
+	}
+}
+
+// Convert a flag or argument name to an upper-case shell variable name.
+func variableName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, name)
+}
+
+// Format a value for a shell assignment. Lists are formatted as bash arrays.
+func shellValue(v reflect.Value) string {
+	switch value := v.Interface().(type) {
+	case []string:
+		quoted := make([]string, len(value))
+		for i, el := range value {
+			quoted[i] = shellQuote(el)
+		}
+		return "(" + strings.Join(quoted, " ") + ")"
+	case time.Duration:
+		return shellQuote(value.String())
+	default:
+		return shellQuote(fmt.Sprintf("%v", value))
+	}
+}
+
+// Quote s for the shell using single quotes.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
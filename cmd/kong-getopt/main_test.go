@@ -0,0 +1,123 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `{
+  "name": "deploy",
+  "description": "Deploy things.",
+  "flags": [
+    {"name": "verbose", "short": "v", "type": "bool", "help": "Don't be quiet, ok?"}
+  ],
+  "commands": [
+    {
+      "name": "app",
+      "help": "Deploy an app.",
+      "flags": [
+        {"name": "region", "enum": "eu,us", "default": "eu"},
+        {"name": "tags", "type": "list"},
+        {"name": "timeout", "type": "duration", "default": "30s"}
+      ],
+      "args": [
+        {"name": "target", "help": "Target."}
+      ]
+    }
+  ]
+}`
+
+func runTest(t *testing.T, prefix string, args ...string) (string, string, int) {
+	t.Helper()
+	stdout := &strings.Builder{}
+	stderr := &strings.Builder{}
+	status := run(strings.NewReader(testSpec), prefix, args, stdout, stderr)
+	return stdout.String(), stderr.String(), status
+}
+
+func TestAssignments(t *testing.T) {
+	stdout, stderr, status := runTest(t, "", "-v", "app", "it's here", "--tags=a,b c", "--region=us")
+	require.Equal(t, 0, status)
+	require.Equal(t, "", stderr)
+	require.Equal(t, `COMMAND='app'
+VERBOSE='true'
+REGION='us'
+TAGS=('a' 'b c')
+TIMEOUT='30s'
+TARGET='it'\''s here'
+`, stdout)
+}
+
+func TestAssignmentsPrefix(t *testing.T) {
+	stdout, _, status := runTest(t, "OPT_", "app", "web")
+	require.Equal(t, 0, status)
+	require.Contains(t, stdout, "OPT_COMMAND='app'\n")
+	require.Contains(t, stdout, "OPT_VERBOSE='false'\n")
+	require.Contains(t, stdout, "OPT_TAGS=()\n")
+}
+
+func TestHelp(t *testing.T) {
+	stdout, stderr, status := runTest(t, "", "--help")
+	require.Equal(t, 0, status)
+	require.Equal(t, "exit 0\n", stdout)
+	require.Contains(t, stderr, "Usage: deploy <command>")
+	require.Contains(t, stderr, "-v, --verbose    Don't be quiet, ok?")
+}
+
+func TestParseError(t *testing.T) {
+	stdout, stderr, status := runTest(t, "", "app", "web", "--region=mars")
+	require.Equal(t, usageExitCode, status)
+	require.Equal(t, "exit 2\n", stdout)
+	require.Equal(t, "deploy: error: --region must be one of \"eu\",\"us\" but got \"mars\"\n", stderr)
+}
+
+func TestInvalidSpec(t *testing.T) {
+	stdout := &strings.Builder{}
+	stderr := &strings.Builder{}
+	status := run(strings.NewReader(`{"flags": [{"name": "size", "type": "complex"}]}`), "", nil, stdout, stderr)
+	require.Equal(t, 1, status)
+	require.Equal(t, "exit 1\n", stdout.String())
+	require.Equal(t, "kong-getopt: error: invalid spec: size: unsupported type \"complex\"\n", stderr.String())
+}
+
+func TestInvalidGrammar(t *testing.T) {
+	stdout := &strings.Builder{}
+	stderr := &strings.Builder{}
+	status := run(strings.NewReader(`{"flags": [{"name": "size"}, {"name": "size"}]}`), "", nil, stdout, stderr)
+	require.Equal(t, 1, status)
+	require.Equal(t, "exit 1\n", stdout.String())
+	require.Contains(t, stderr.String(), "kong-getopt: error: invalid spec: ")
+}
+
+func TestInvalidVariableNames(t *testing.T) {
+	tests := []struct {
+		spec string
+		err  string
+	}{
+		{`{"flags": [{"name": "1st"}]}`, `1st: name must not start with a digit`},
+		{`{"flags": [{"name": "command"}]}`, `command: variable COMMAND is already used by the selected command`},
+		{`{"flags": [{"name": "dry-run"}, {"name": "dry_run"}]}`, `dry_run: variable DRY_RUN is already used by dry-run`},
+		{`{"flags": [{"name": "target"}], "commands": [{"name": "app", "args": [{"name": "target"}]}]}`, `app: target: variable TARGET is already used by target`},
+	}
+	for _, test := range tests {
+		// nolint: scopelint
+		t.Run(test.spec, func(t *testing.T) {
+			stdout := &strings.Builder{}
+			stderr := &strings.Builder{}
+			status := run(strings.NewReader(test.spec), "", nil, stdout, stderr)
+			require.Equal(t, 1, status)
+			require.Equal(t, "exit 1\n", stdout.String())
+			require.Equal(t, "kong-getopt: error: invalid spec: "+test.err+"\n", stderr.String())
+		})
+	}
+}
+
+func TestSiblingCommandsMayReuseNames(t *testing.T) {
+	spec := `{"commands": [{"name": "a", "args": [{"name": "target"}]}, {"name": "b", "args": [{"name": "target"}]}]}`
+	stdout := &strings.Builder{}
+	status := run(strings.NewReader(spec), "", []string{"b", "web"}, stdout, &strings.Builder{})
+	require.Equal(t, 0, status)
+	require.Equal(t, "COMMAND='b'\nTARGET='web'\n", stdout.String())
+}
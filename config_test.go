@@ -2,6 +2,7 @@ package kong_test
 
 import (
 	"encoding/json"
+	"io"
 	"io/ioutil"
 	"os"
 	"testing"
@@ -43,6 +44,18 @@ func TestConfigValidation(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestConfigPathInterpolation(t *testing.T) {
+	var cli struct {
+		Flag string
+	}
+	path, cleanup := writeConfig(t, `{"flag": "from-config"}`)
+	defer cleanup()
+	p := mustNew(t, &cli, kong.Vars{"config": path}, kong.Configuration(kong.JSON, "${config}"))
+	_, err := p.Parse(nil)
+	require.NoError(t, err)
+	require.Equal(t, "from-config", cli.Flag)
+}
+
 func makeConfig(t *testing.T, config interface{}) (path string, cleanup func()) {
 	t.Helper()
 	w, err := ioutil.TempFile("", "")
@@ -52,3 +65,93 @@ func makeConfig(t *testing.T, config interface{}) (path string, cleanup func())
 	require.NoError(t, err)
 	return w.Name(), func() { os.Remove(w.Name()) }
 }
+
+func TestCommandConfigFiles(t *testing.T) {
+	type cliType struct {
+		Region string
+		Deploy struct {
+			Target string
+			Prod   struct {
+				Replicas int
+			} `cmd:""`
+		} `cmd:"" configfiles:"${deploy_config},/does/not/exist.json"`
+		Logs struct {
+			Target string
+		} `cmd:""`
+	}
+	appConfig, cleanApp := writeConfig(t, `{"region": "us", "target": "app"}`)
+	defer cleanApp()
+	deployConfig, cleanDeploy := writeConfig(t, `{"region": "eu", "target": "deploy", "replicas": 3}`)
+	defer cleanDeploy()
+
+	var cli cliType
+	p := mustNew(t, &cli,
+		kong.Vars{"deploy_config": deployConfig},
+		kong.Configuration(kong.JSON, appConfig))
+	_, err := p.Parse([]string{"deploy", "prod"})
+	require.NoError(t, err)
+	require.Equal(t, "us", cli.Region, "parent flags are out of the command's scope")
+	require.Equal(t, "deploy", cli.Deploy.Target)
+	require.Equal(t, 3, cli.Deploy.Prod.Replicas)
+
+	cli = cliType{}
+	p = mustNew(t, &cli,
+		kong.Vars{"deploy_config": deployConfig},
+		kong.Configuration(kong.JSON, appConfig))
+	_, err = p.Parse([]string{"logs"})
+	require.NoError(t, err)
+	require.Equal(t, "app", cli.Logs.Target)
+	require.Equal(t, "", cli.Deploy.Target)
+}
+
+func TestCommandConfigFilesBatchResolver(t *testing.T) {
+	var cli struct {
+		Region string
+		Deploy struct {
+			Target   string
+			Replicas int
+			Canary   bool
+		} `cmd:"" configfiles:"${deploy_config}"`
+	}
+	deployConfig, cleanup := writeConfig(t, `{}`)
+	defer cleanup()
+	resolver := &countingBatchResolver{values: map[string]interface{}{"region": "eu", "target": "web", "replicas": 3}}
+	loader := func(r io.Reader) (kong.Resolver, error) { return resolver, nil }
+	_, err := mustNew(t, &cli, kong.Vars{"deploy_config": deployConfig}, kong.Configuration(loader)).Parse([]string{"deploy"})
+	require.NoError(t, err)
+	require.Equal(t, "", cli.Region)
+	require.Equal(t, "web", cli.Deploy.Target)
+	require.Equal(t, 3, cli.Deploy.Replicas)
+	require.Equal(t, 1, resolver.calls)
+	require.Equal(t, []string{"target", "replicas", "canary"}, resolver.flags)
+}
+
+func TestCommandConfigFilesInterpolation(t *testing.T) {
+	var cli struct {
+		Deploy struct {
+			Target string
+		} `cmd:"" configfiles:"${deploy_config},${missing}"`
+	}
+	_, err := kong.New(&cli, kong.Vars{"deploy_config": "deploy.json"}, kong.Configuration(kong.JSON))
+	require.EqualError(t, err, "configfiles for deploy: undefined variable ${missing}")
+}
+
+func TestCommandConfigFilesMalformed(t *testing.T) {
+	var cli struct {
+		Deploy struct {
+			Target string
+		} `cmd:"" configfiles:"${deploy_config}"`
+	}
+	deployConfig, cleanup := writeConfig(t, `{"target": `)
+	defer cleanup()
+	_, err := mustNew(t, &cli, kong.Vars{"deploy_config": deployConfig}, kong.Configuration(kong.JSON)).Parse([]string{"deploy"})
+	require.EqualError(t, err, deployConfig+": unexpected EOF")
+}
+
+func TestCommandConfigFilesRequireLoader(t *testing.T) {
+	var cli struct {
+		Deploy struct{} `cmd:"" configfiles:"deploy.json"`
+	}
+	_, err := kong.New(&cli)
+	require.EqualError(t, err, "deploy: configfiles requires kong.Configuration(...)")
+}
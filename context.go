@@ -2,6 +2,7 @@ package kong
 
 import (
 	"fmt"
+	"os"
 	"reflect"
 	"sort"
 	"strconv"
@@ -26,6 +27,9 @@ type Path struct {
 
 	// True if this Path element was created as the result of a resolver.
 	Resolved bool
+
+	// True if the resolved value was decrypted. Secret values are redacted from error messages.
+	Secret bool
 }
 
 // Node returns the Node associated with this Path, or nil if Path is a non-Node.
@@ -57,6 +61,19 @@ type Context struct {
 	bindings  bindings
 	resolvers []Resolver // Extra context-specific resolvers.
 	scan      *Scanner
+
+	batches map[batchKey]map[*Flag]interface{} // Values resolved by BatchResolvers.
+
+	current   Token        // Token being traced.
+	rewritten map[int]bool // Positions of arguments rewritten by Preprocessors.
+	envArgs   int          // Number of leading arguments from the EnvArgs() environment variable.
+	deferred  []Token      // Arguments from the environment deferred until a command is selected.
+}
+
+// Key for values resolved by the BatchResolver at index "resolver" for a Path.
+type batchKey struct {
+	resolver int
+	path     *Path
 }
 
 // Trace path of "args" through the grammar tree.
@@ -66,6 +83,11 @@ type Context struct {
 // This just constructs a new trace. To fully apply the trace you must call Reset(), Resolve(),
 // Validate() and Apply().
 func Trace(k *Kong, args []string) (*Context, error) {
+	return trace(k, args, 0)
+}
+
+// Trace args, the first envArgs of which are from the EnvArgs() environment variable.
+func trace(k *Kong, args []string, envArgs int) (*Context, error) {
 	c := &Context{
 		Kong: k,
 		Args: args,
@@ -75,11 +97,45 @@ func Trace(k *Kong, args []string) (*Context, error) {
 		values:   map[*Value]reflect.Value{},
 		scan:     Scan(args...),
 		bindings: bindings{},
+		envArgs:  envArgs,
+	}
+	if c.Error = c.preprocess(); c.Error != nil {
+		return c, nil
 	}
 	c.Error = c.trace(c.Model.Node)
+	// Refer to the original argument if the error occurred in a token rewritten by a Preprocessor.
+	if pos := c.current.Position; c.Error != nil && c.rewritten[pos] {
+		c.Error = fmt.Errorf("%s (in argument %d %q)", c.Error, pos, args[pos-1])
+	}
+	// Attribute errors in arguments from the environment to the variable.
+	if c.Error != nil && c.fromEnvArgs(c.current) {
+		c.Error = errors.Wrapf(c.Error, "$%s", k.envArgsName())
+	}
 	return c, nil
 }
 
+// Apply Preprocessors to the command-line tokens, in order.
+func (c *Context) preprocess() error {
+	if len(c.Kong.preprocessors) == 0 {
+		return nil
+	}
+	tokens := c.scan.tokens()
+	for _, preprocessor := range c.Kong.preprocessors {
+		var err error
+		if tokens, err = preprocessor(tokens); err != nil {
+			return err
+		}
+	}
+	c.rewritten = map[int]bool{}
+	for _, token := range tokens {
+		if token.Position > 0 && token.Position <= len(c.Args) && (token.Type != UntypedToken || token.Value != c.Args[token.Position-1]) {
+			c.rewritten[token.Position] = true
+		}
+	}
+	c.scan = ScanFromTokens(tokens...)
+	return nil
+}
+
 // Bind adds bindings to the Context.
 func (c *Context) Bind(args ...interface{}) {
 	c.bindings.add(args...)
@@ -133,11 +189,39 @@ func (c *Context) Empty() bool {
 }
 
 // Validate the current context.
+//
+// By default the first problem found is returned. If the AggregateErrors() option is used, all problems are returned
+// as ValidationErrors.
 func (c *Context) Validate() error { // nolint: gocyclo
+	errs := ValidationErrors{}
+	seen := map[string]bool{}
+	// Record an error, returning true if validation should stop.
+	report := func(err error) bool {
+		if err == nil {
+			return false
+		}
+		if !seen[err.Error()] {
+			seen[err.Error()] = true
+			errs = append(errs, err)
+		}
+		return !c.Kong.aggregateErrors
+	}
+	result := func() error {
+		switch len(errs) {
+		case 0:
+			return nil
+		case 1:
+			return errs[0]
+		default:
+			return errs
+		}
+	}
+
+	secrets := c.secretValues()
 	err := Visit(c.Model, func(node Visitable, next Next) error {
 		if value, ok := node.(*Value); ok {
 			if value.Enum != "" && (!value.Required || value.Default != "") {
-				if err := checkEnum(value, value.Target); err != nil {
+				if err := checkEnum(value, value.Target, secrets[value]); report(err) {
 					return err
 				}
 			}
@@ -145,13 +229,18 @@ func (c *Context) Validate() error { // nolint: gocyclo
 		return next(nil)
 	})
 	if err != nil {
-		return err
+		return result()
 	}
 	for _, resolver := range c.combineResolvers() {
-		if err := resolver.Validate(c.Model); err != nil {
-			return err
+		if report(resolver.Validate(c.Model)) {
+			return result()
 		}
 	}
+	// Check the terminal node.
+	node := c.Selected()
+	if node == nil {
+		node = c.Model.Node
+	}
 	for _, path := range c.Path {
 		var value *Value
 		switch {
@@ -162,19 +251,14 @@ func (c *Context) Validate() error { // nolint: gocyclo
 			value = path.Positional
 		}
 		if value != nil && value.Tag.Enum != "" {
-			if err := checkEnum(value, value.Target); err != nil {
-				return err
+			if report(checkEnum(value, value.Target, secrets[value])) {
+				return result()
 			}
 		}
-		if err := checkMissingFlags(path.Flags); err != nil {
-			return err
+		if report(checkMissingFlags(node, path.Flags)) {
+			return result()
 		}
 	}
-	// Check the terminal node.
-	node := c.Selected()
-	if node == nil {
-		node = c.Model.Node
-	}
 
 	// Find deepest positional argument so we can check if all required positionals have been provided.
 	positionals := 0
@@ -184,23 +268,30 @@ func (c *Context) Validate() error { // nolint: gocyclo
 		}
 	}
 
+	// Missing children include missing positionals, so only check the latter separately if there are none.
 	if err := checkMissingChildren(node); err != nil {
-		return err
+		if report(err) {
+			return result()
+		}
+	} else if report(checkMissingPositionals(positionals, node.Positional)) {
+		return result()
 	}
-	if err := checkMissingPositionals(positionals, node.Positional); err != nil {
-		return err
+	for _, err := range checkXorDuplicates(c.Path) {
+		if report(err) {
+			return result()
+		}
 	}
-	if err := checkXorDuplicates(c.Path); err != nil {
-		return err
+	if report(checkPairedDuplicates(c.Path)) {
+		return result()
 	}
 
 	if node.Type == ArgumentNode {
 		value := node.Argument
 		if value.Required && !value.Set {
-			return fmt.Errorf("%s is required", node.Summary())
+			report(fmt.Errorf("%s is required", node.Summary()))
 		}
 	}
-	return nil
+	return result()
 }
 
 // Flags returns the accumulated available flags.
@@ -236,6 +327,34 @@ func (c *Context) AddResolver(resolver Resolver) {
 	c.resolvers = append(c.resolvers, resolver)
 }
 
+// Load the "configfiles" of each selected command, scoped to the command's flags.
+//
+// Command configuration takes precedence over Configuration() files, but not over resolvers added with AddResolver(),
+// such as by ConfigFlag.
+func (c *Context) addCommandConfigs() error {
+	resolvers := []Resolver{}
+	for _, path := range c.Path {
+		if path.Command == nil {
+			continue
+		}
+		for _, file := range path.Command.Tag.ConfigFiles {
+			resolver, err := c.Kong.openConfig(file)
+			if os.IsNotExist(errors.Cause(err)) {
+				continue
+			}
+			if err != nil {
+				return errors.Wrap(err, file)
+			}
+			if err := c.Kong.applyConfigMigrations(file, resolver); err != nil {
+				return err
+			}
+			resolvers = append(resolvers, newScopedResolver(resolver, path.Command))
+		}
+	}
+	c.resolvers = append(resolvers, c.resolvers...)
+	return nil
+}
+
 // FlagValue returns the set value of a flag if it was encountered and exists, or its default value.
 func (c *Context) FlagValue(flag *Flag) interface{} {
 	for _, trace := range c.Path {
@@ -271,8 +390,15 @@ func (c *Context) trace(node *Node) (err error) { // nolint: gocyclo
 		flags = append(flags, group...)
 	}
 
+	// Retry arguments from the environment that were deferred until a command was selected.
+	for i := len(c.deferred) - 1; i >= 0; i-- {
+		c.scan.PushToken(c.deferred[i])
+	}
+	c.deferred = nil
+
 	for !c.scan.Peek().IsEOL() {
 		token := c.scan.Peek()
+		c.current = token
 		switch token.Type {
 		case UntypedToken:
 			switch v := token.Value.(type) {
@@ -283,22 +409,22 @@ func (c *Context) trace(node *Node) (err error) { // nolint: gocyclo
 					fallthrough
 				default: // nolint
 					c.scan.Pop()
-					c.scan.PushTyped(token.Value, PositionalArgumentToken)
+					c.scan.PushToken(Token{Value: token.Value, Type: PositionalArgumentToken, Position: token.Position})
 
 				// Indicates end of parsing. All remaining arguments are treated as positional arguments only.
 				case v == "--":
 					c.scan.Pop()
-					args := []string{}
+					args := []Token{}
 					for {
 						token = c.scan.Pop()
 						if token.Type == EOLToken {
 							break
 						}
-						args = append(args, token.String())
+						args = append(args, Token{Value: token.String(), Type: PositionalArgumentToken, Position: token.Position})
 					}
 					// Note: tokens must be pushed in reverse order.
 					for i := range args {
-						c.scan.PushTyped(args[len(args)-1-i], PositionalArgumentToken)
+						c.scan.PushToken(args[len(args)-1-i])
 					}
 
 				// Long flag.
@@ -307,38 +433,36 @@ func (c *Context) trace(node *Node) (err error) { // nolint: gocyclo
 					// Parse it and push the tokens.
 					parts := strings.SplitN(v[2:], "=", 2)
 					if len(parts) > 1 {
-						c.scan.PushTyped(parts[1], FlagValueToken)
+						c.scan.PushToken(Token{Value: parts[1], Type: FlagValueToken, Position: token.Position})
 					}
-					c.scan.PushTyped(parts[0], FlagToken)
+					c.scan.PushToken(Token{Value: parts[0], Type: FlagToken, Position: token.Position})
 
 				// Short flag.
 				case strings.HasPrefix(v, "-"):
 					c.scan.Pop()
 					// Note: tokens must be pushed in reverse order.
 					if tail := v[2:]; tail != "" {
-						c.scan.PushTyped(tail, ShortFlagTailToken)
+						c.scan.PushToken(Token{Value: tail, Type: ShortFlagTailToken, Position: token.Position})
 					}
-					c.scan.PushTyped(v[1:2], ShortFlagToken)
+					c.scan.PushToken(Token{Value: v[1:2], Type: ShortFlagToken, Position: token.Position})
 				}
 			default:
 				c.scan.Pop()
-				c.scan.PushTyped(token.Value, PositionalArgumentToken)
+				c.scan.PushToken(Token{Value: token.Value, Type: PositionalArgumentToken, Position: token.Position})
 			}
 
 		case ShortFlagTailToken:
 			c.scan.Pop()
 			// Note: tokens must be pushed in reverse order.
 			if tail := token.String()[1:]; tail != "" {
-				c.scan.PushTyped(tail, ShortFlagTailToken)
+				c.scan.PushToken(Token{Value: tail, Type: ShortFlagTailToken, Position: token.Position})
 			}
-			c.scan.PushTyped(token.String()[0:1], ShortFlagToken)
+			c.scan.PushToken(Token{Value: token.String()[0:1], Type: ShortFlagToken, Position: token.Position})
 
-		case FlagToken:
-			if err := c.parseFlag(flags, token.String()); err != nil {
-				return err
+		case FlagToken, ShortFlagToken:
+			if c.deferEnvArgs(node, flags, token) {
+				break
 			}
-
-		case ShortFlagToken:
 			if err := c.parseFlag(flags, token.String()); err != nil {
 				return err
 			}
@@ -352,6 +476,9 @@ func (c *Context) trace(node *Node) (err error) { // nolint: gocyclo
 			// Ensure we've consumed all positional arguments.
 			if positional < len(node.Positional) {
 				arg := node.Positional[positional]
+				if arg.Tag.NoEnv && c.fromEnvArgs(token) {
+					return arg.restrictedSourceError("from the environment")
+				}
 				err := arg.Parse(c.scan, c.getValue(arg))
 				if err != nil {
 					return err
@@ -395,19 +522,43 @@ func (c *Context) trace(node *Node) (err error) { // nolint: gocyclo
 				}
 			}
 
-			return findPotentialCandidates(token.String(), candidates, "unexpected argument %s", token)
+			return c.unexpectedArgument(node, token, candidates)
 		default:
 			return fmt.Errorf("unexpected token %s", token)
 		}
 	}
+	if len(c.deferred) > 0 {
+		// No command accepted the deferred arguments, so report the first of them.
+		c.current = c.deferred[0]
+		return c.parseFlag(flags, c.current.String())
+	}
 	return c.maybeSelectDefault(flags, node)
 }
 
+// Flags from the environment may belong to a command selected later on the command-line, so if token is from the
+// environment and not a flag of node, defer the remaining arguments from the environment until a command is selected.
+//
+// Returns true if the arguments were deferred.
+func (c *Context) deferEnvArgs(node *Node, flags []*Flag, token Token) bool {
+	if !c.fromEnvArgs(token) || len(node.Children) == 0 {
+		return false
+	}
+	for _, flag := range flags {
+		if (!flag.ShortOnly && token.String() == "--"+flag.Name) || (flag.Short != 0 && token.String() == "-"+string(flag.Short)) {
+			return false
+		}
+	}
+	for c.fromEnvArgs(c.scan.Peek()) {
+		c.deferred = append(c.deferred, c.scan.Pop())
+	}
+	return true
+}
+
 // End of the line, check for a default command, but only if we're not displaying help,
 // otherwise we'd only ever display the help for the default command.
 func (c *Context) maybeSelectDefault(flags []*Flag, node *Node) error {
 	for _, flag := range flags {
-		if flag.Name == "help" && flag.Set {
+		if flag == c.Model.HelpFlag && flag.Set {
 			return nil
 		}
 	}
@@ -444,32 +595,131 @@ func (c *Context) Resolve() error {
 			if _, ok := c.values[flag.Value]; ok {
 				continue
 			}
-			for _, resolver := range resolvers {
-				s, err := resolver.Resolve(c, path, flag)
-				if err != nil {
-					return err
-				}
-				if s == nil {
-					continue
-				}
-
-				scan := Scan().PushTyped(s, FlagValueToken)
-				delete(c.values, flag.Value)
-				err = flag.Parse(scan, c.getValue(flag.Value))
-				if err != nil {
-					return err
-				}
-				inserted = append(inserted, &Path{
-					Flag:     flag,
-					Resolved: true,
-				})
+			resolved, err := c.resolveFlag(resolvers, path, flag)
+			if err != nil {
+				return err
 			}
+			inserted = append(inserted, resolved...)
 		}
 	}
 	c.Path = append(inserted, c.Path...)
 	return nil
 }
 
+// Apply resolvers to a single flag, returning a Path element for each resolver that provided a value.
+func (c *Context) resolveFlag(resolvers []Resolver, path *Path, flag *Flag) ([]*Path, error) {
+	inserted := []*Path{}
+	if flag.Tag.NoConfig {
+		return nil, nil
+	}
+	for i, resolver := range resolvers {
+		var (
+			s   interface{}
+			err error
+		)
+		if batch, ok := resolver.(BatchResolver); ok {
+			s, err = c.resolveBatch(i, batch, path, flag)
+		} else {
+			s, err = resolver.Resolve(c, path, flag)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if s == nil {
+			continue
+		}
+		if c.Kong.expandResolved {
+			s, err = c.expandResolvedValue(path.Node(), s)
+			if err != nil {
+				return nil, errors.Wrapf(err, "%s: failed to expand configuration value", resolverKey(resolver, flag))
+			}
+		}
+		s, secret, err := c.decrypt(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s: failed to decrypt value", flag.ShortSummary())
+		}
+
+		scan := Scan().PushTyped(s, FlagValueToken)
+		delete(c.values, flag.Value)
+		err = flag.Parse(scan, c.getValue(flag.Value))
+		if err != nil {
+			if secret {
+				// Don't leak the decrypted value.
+				return nil, errors.Errorf("%s: invalid value %s from encrypted configuration", flag.ShortSummary(), redacted)
+			}
+			return nil, err
+		}
+		inserted = append(inserted, &Path{
+			Flag:     flag,
+			Resolved: true,
+			Secret:   secret,
+		})
+	}
+	return inserted, nil
+}
+
+// Resolve a flag with a BatchResolver, resolving all unset flags of path on first use and caching the result.
+func (c *Context) resolveBatch(index int, resolver BatchResolver, path *Path, flag *Flag) (interface{}, error) {
+	key := batchKey{resolver: index, path: path}
+	values, ok := c.batches[key]
+	if !ok {
+		flags := []*Flag{}
+		for _, f := range path.Flags {
+			if _, ok := c.values[f.Value]; !ok && !f.Tag.NoConfig {
+				flags = append(flags, f)
+			}
+		}
+		var err error
+		values, err = resolver.ResolveAll(c, path, flags)
+		if err != nil {
+			return nil, err
+		}
+		if c.batches == nil {
+			c.batches = map[batchKey]map[*Flag]interface{}{}
+		}
+		c.batches[key] = values
+	}
+	return values[flag], nil
+}
+
+// Expand variables in a resolved string value, or in the string elements of a resolved list or map.
+func (c *Context) expandResolvedValue(node *Node, value interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case string:
+		if !strings.Contains(value, "$") {
+			return value, nil
+		}
+		vars := Vars{}
+		for _, env := range os.Environ() {
+			parts := strings.SplitN(env, "=", 2)
+			vars[parts[0]] = parts[1]
+		}
+		vars = vars.CloneWith(c.Kong.vars).CloneWith(node.Vars())
+		return interpolateEscaped(value, vars)
+
+	case []interface{}:
+		out := make([]interface{}, len(value))
+		for i, el := range value {
+			var err error
+			if out[i], err = c.expandResolvedValue(node, el); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(value))
+		for key, el := range value {
+			var err error
+			if out[key], err = c.expandResolvedValue(node, el); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+	return value, nil
+}
+
 // Combine application-level resolvers and context resolvers.
 func (c *Context) combineResolvers() []Resolver {
 	resolvers := []Resolver{}
@@ -538,13 +788,22 @@ func (c *Context) Apply() (string, error) {
 	return strings.Join(path, " "), nil
 }
 
+// Returns true if token originated from the EnvArgs() environment variable rather than the command-line.
+func (c *Context) fromEnvArgs(token Token) bool {
+	return token.Position > 0 && token.Position <= c.envArgs
+}
+
 func (c *Context) parseFlag(flags []*Flag, match string) (err error) {
 	defer catch(&err)
 	candidates := []string{}
 	for _, flag := range flags {
 		long := "--" + flag.Name
 		short := "-" + string(flag.Short)
-		candidates = append(candidates, long)
+		if flag.ShortOnly {
+			long = ""
+		} else {
+			candidates = append(candidates, long)
+		}
 		if flag.Short != 0 {
 			candidates = append(candidates, short)
 		}
@@ -552,10 +811,20 @@ func (c *Context) parseFlag(flags []*Flag, match string) (err error) {
 			continue
 		}
 		// Found a matching flag.
+		if c.fromEnvArgs(c.current) {
+			if flag.Tag.NoEnv {
+				return flag.restrictedSourceError("from the environment")
+			}
+		} else if flag.Tag.NoCLI {
+			return flag.restrictedSourceError("on the command-line")
+		}
 		c.scan.Pop()
 		err := flag.Parse(c.scan, c.getValue(flag.Value))
 		if err != nil {
 			if e, ok := errors.Cause(err).(*expectedError); ok && e.token.InferredType().IsAny(FlagToken, ShortFlagToken) {
+				if flag.ShortOnly {
+					return errors.Errorf("%s; perhaps try %s%q?", err, flag.ShortSummary(), e.token)
+				}
 				return errors.Errorf("%s; perhaps try %s=%q?", err, flag.ShortSummary(), e.token)
 			}
 			return err
@@ -566,10 +835,33 @@ func (c *Context) parseFlag(flags []*Flag, match string) (err error) {
 	return findPotentialCandidates(match, candidates, "unknown flag %s", match)
 }
 
+// RunOrder controls the order in which Context.Run() calls Run() methods in the command hierarchy.
+type RunOrder int
+
+// Orders in which Run() methods are called.
+const (
+	// LeafFirst calls Run() on the selected command first, then its parent, and so on up to the root. This is the
+	// default.
+	LeafFirst RunOrder = iota
+	// RootFirst calls Run() on the root first, then its children, down to the selected command.
+	RootFirst
+	// LeafOnly only calls Run() on the selected command.
+	LeafOnly
+)
+
+// ErrStopPropagation may be returned by a Run() method to prevent any further Run() methods being called.
+//
+// Context.Run() will then return nil.
+var ErrStopPropagation = errors.New("stop propagation")
+
 // RunNode calls the Run() method on an arbitrary node.
 //
 // This is useful in conjunction with Visit(), for dynamically running commands.
 //
+// Run() methods of the node and its ancestors are called in the order configured with the RunOrdering() option,
+// which defaults to the node first, then its parent, and so on up to the root. If a Run() method returns
+// ErrStopPropagation, no further Run() methods are called.
+//
 // Any passed values will be bindable to arguments of the target Run() method. Additionally,
 // all parent nodes in the command structure will be bound.
 func (c *Context) RunNode(node *Node, binds ...interface{}) (err error) {
@@ -580,7 +872,11 @@ func (c *Context) RunNode(node *Node, binds ...interface{}) (err error) {
 	}
 	methodBinds := c.Kong.bindings.clone().add(binds...).add(c).merge(c.bindings)
 	methods := []targetMethod{}
+	target := node
 	for i := 0; node != nil; i, node = i+1, node.Parent {
+		if c.Kong.runOrder == LeafOnly && i > 0 {
+			break
+		}
 		method := getMethod(node.Target, "Run")
 		methodBinds = methodBinds.clone()
 		for p := node; p != nil; p = p.Parent {
@@ -591,15 +887,26 @@ func (c *Context) RunNode(node *Node, binds ...interface{}) (err error) {
 		}
 	}
 	if len(methods) == 0 {
-		return fmt.Errorf("no Run() method found in hierarchy of %s", c.Selected().Summary())
+		if c.Kong.runOrder == LeafOnly {
+			return fmt.Errorf("no Run() method found on %s", target.Summary())
+		}
+		return fmt.Errorf("no Run() method found in hierarchy of %s", target.Summary())
 	}
 	_, err = c.Apply()
 	if err != nil {
 		return err
 	}
 
+	if c.Kong.runOrder == RootFirst {
+		for i, j := 0, len(methods)-1; i < j; i, j = i+1, j-1 {
+			methods[i], methods[j] = methods[j], methods[i]
+		}
+	}
 	for _, method := range methods {
 		if err = callMethod("Run", method.node.Target, method.method, method.binds); err != nil {
+			if errors.Cause(err) == ErrStopPropagation {
+				return nil
+			}
 			return err
 		}
 	}
@@ -629,10 +936,10 @@ func (c *Context) PrintUsage(summary bool) error {
 	return nil
 }
 
-func checkMissingFlags(flags []*Flag) error {
+func checkMissingFlags(node *Node, flags []*Flag) error {
 	missing := []string{}
 	for _, flag := range flags {
-		if !flag.Required || flag.Set {
+		if !node.RequiresFlag(flag) || flag.Set {
 			continue
 		}
 		missing = append(missing, flag.Summary())
@@ -649,7 +956,7 @@ func checkMissingChildren(node *Node) error {
 
 	missingArgs := []string{}
 	for _, arg := range node.Positional {
-		if arg.Required && !arg.Set {
+		if arg.Required && !arg.Set && !arg.setByPaired() {
 			missingArgs = append(missingArgs, arg.Summary())
 		}
 	}
@@ -700,8 +1007,8 @@ func checkMissingPositionals(positional int, values []*Value) error {
 		return nil
 	}
 
-	// We're low on supplied positionals, but the missing one is optional.
-	if !values[positional].Required {
+	// We're low on supplied positionals, but the missing one is optional or was set with a flag.
+	if !values[positional].Required || values[positional].setByPaired() {
 		return nil
 	}
 
@@ -712,11 +1019,26 @@ func checkMissingPositionals(positional int, values []*Value) error {
 	return fmt.Errorf("missing positional arguments %s", strings.Join(missing, " "))
 }
 
-func checkEnum(value *Value, target reflect.Value) error {
+// Placeholder for secret values, such as decrypted configuration values, in error messages.
+const redacted = "<redacted>"
+
+// Values that were set from decrypted configuration values, which must not be included in error messages.
+func (c *Context) secretValues() map[*Value]bool {
+	secrets := map[*Value]bool{}
+	for _, path := range c.Path {
+		if path.Secret && path.Flag != nil {
+			secrets[path.Flag.Value] = true
+		}
+	}
+	return secrets
+}
+
+// Check that target is one of value's enums. If secret is true, the value is redacted from the error.
+func checkEnum(value *Value, target reflect.Value, secret bool) error {
 	switch target.Kind() {
 	case reflect.Slice, reflect.Array:
 		for i := 0; i < target.Len(); i++ {
-			if err := checkEnum(value, target.Index(i)); err != nil {
+			if err := checkEnum(value, target.Index(i), secret); err != nil {
 				return err
 			}
 		}
@@ -736,11 +1058,15 @@ func checkEnum(value *Value, target reflect.Value) error {
 			enums = append(enums, fmt.Sprintf("%q", enum))
 		}
 		sort.Strings(enums)
-		return fmt.Errorf("%s must be one of %s but got %q", value.ShortSummary(), strings.Join(enums, ","), target.Interface())
+		got := fmt.Sprintf("%q", target.Interface())
+		if secret {
+			got = redacted
+		}
+		return fmt.Errorf("%s must be one of %s but got %s", value.ShortSummary(), strings.Join(enums, ","), got)
 	}
 }
 
-func checkXorDuplicates(paths []*Path) error {
+func checkXorDuplicates(paths []*Path) (errs []error) {
 	for _, path := range paths {
 		seen := map[string]*Flag{}
 		for _, flag := range path.Flags {
@@ -751,21 +1077,105 @@ func checkXorDuplicates(paths []*Path) error {
 				continue
 			}
 			if seen[flag.Xor] != nil {
-				return fmt.Errorf("--%s and --%s can't be used together", seen[flag.Xor].Name, flag.Name)
+				errs = append(errs, fmt.Errorf("%s and %s can't be used together", seen[flag.Xor].ShortSummary(), flag.ShortSummary()))
+				continue
 			}
 			seen[flag.Xor] = flag
 		}
 	}
+	return errs
+}
+
+// Check that fields that are both a positional argument and a flag weren't given as both on the command-line.
+func checkPairedDuplicates(paths []*Path) error {
+	positionals := map[*Value]bool{}
+	for _, path := range paths {
+		if path.Positional != nil && path.Positional.Paired != nil {
+			positionals[path.Positional] = true
+		}
+	}
+	for _, path := range paths {
+		if path.Flag != nil && !path.Resolved && positionals[path.Flag.Paired] {
+			return fmt.Errorf("<%s> and %s can't both be given", path.Flag.Name, path.Flag.ShortSummary())
+		}
+	}
 	return nil
 }
 
+// Report an unexpected argument, suggesting similar commands under node or, failing that, anywhere in the
+// application.
+func (c *Context) unexpectedArgument(node *Node, token Token, candidates []string) error {
+	needle := token.String()
+	for _, candidate := range candidates {
+		if isPotentialCandidate(candidate, needle) {
+			return findPotentialCandidates(needle, candidates, "unexpected argument %s", token)
+		}
+	}
+	paths := findCommandPaths(c.Model, node, needle)
+	switch len(paths) {
+	case 0:
+		return fmt.Errorf("unexpected argument %s", token)
+	case 1:
+		return fmt.Errorf("unexpected argument %s, did you mean %q?", token, paths[0])
+	default:
+		quoted := []string{}
+		for _, path := range paths {
+			quoted = append(quoted, strconv.Quote(path))
+		}
+		return fmt.Errorf("unexpected argument %s, did you mean one of %s?", token, strings.Join(quoted, ", "))
+	}
+}
+
+// Find the full paths of commands anywhere in app that are similar to needle, excluding the children of node.
+//
+// Paths are ranked by edit distance, then depth.
+func findCommandPaths(app *Application, node *Node, needle string) []string {
+	type match struct {
+		path     string
+		distance int
+		depth    int
+	}
+	matches := []match{}
+	_ = Visit(app, func(v Visitable, next Next) error {
+		cmd, ok := v.(*Node)
+		if !ok {
+			return next(nil)
+		}
+		if cmd.Hidden {
+			return nil
+		}
+		if cmd.Type == CommandNode && cmd.Parent != node && isPotentialCandidate(cmd.Name, needle) {
+			matches = append(matches, match{cmd.FullPath(), levenshtein(cmd.Name, needle), cmd.Depth()})
+		}
+		return next(nil)
+	})
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].depth < matches[j].depth
+	})
+	if len(matches) > 5 {
+		matches = matches[:5]
+	}
+	out := make([]string, 0, len(matches))
+	for _, m := range matches {
+		out = append(out, m.path)
+	}
+	return out
+}
+
+func isPotentialCandidate(candidate, needle string) bool {
+	return strings.HasPrefix(candidate, needle) || levenshtein(candidate, needle) <= 2
+}
+
 func findPotentialCandidates(needle string, haystack []string, format string, args ...interface{}) error {
 	if len(haystack) == 0 {
 		return fmt.Errorf(format, args...)
 	}
 	closestCandidates := []string{}
 	for _, candidate := range haystack {
-		if strings.HasPrefix(candidate, needle) || levenshtein(candidate, needle) <= 2 {
+		if isPotentialCandidate(candidate, needle) {
 			closestCandidates = append(closestCandidates, fmt.Sprintf("%q", candidate))
 		}
 	}
@@ -0,0 +1,119 @@
+package kong
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// A Decrypter decrypts values returned by resolvers, such as secrets committed to configuration files.
+//
+// Decrypters are registered against a prefix with the Decryption() option.
+type Decrypter interface {
+	// Decrypt ciphertext, which will have had its prefix removed.
+	Decrypt(ciphertext string) (string, error)
+}
+
+// DecrypterFunc is a function that adheres to the Decrypter interface.
+type DecrypterFunc func(ciphertext string) (string, error)
+
+func (d DecrypterFunc) Decrypt(ciphertext string) (string, error) { return d(ciphertext) } // nolint: golint
+
+type prefixedDecrypter struct {
+	prefix    string
+	decrypter Decrypter
+}
+
+// Decrypt a resolved value if it is a string with a registered prefix.
+//
+// Returns true if the value was decrypted.
+func (k *Kong) decrypt(value interface{}) (interface{}, bool, error) {
+	s, ok := value.(string)
+	if !ok {
+		return value, false, nil
+	}
+	for _, d := range k.decrypters {
+		if !strings.HasPrefix(s, d.prefix) {
+			continue
+		}
+		plaintext, err := d.decrypter.Decrypt(s[len(d.prefix):])
+		if err != nil {
+			return nil, false, err
+		}
+		return plaintext, true, nil
+	}
+	return value, false, nil
+}
+
+// AESDecrypter is a Decrypter using AES-GCM with a symmetric key.
+//
+// Ciphertext is the base64 encoding of a random nonce followed by the sealed value, as produced by Encrypt().
+type AESDecrypter struct {
+	aead cipher.AEAD
+}
+
+var _ Decrypter = &AESDecrypter{}
+
+// NewAESDecrypter creates a new AESDecrypter from a 16, 24 or 32 byte key.
+func NewAESDecrypter(key []byte) (*AESDecrypter, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &AESDecrypter{aead: aead}, nil
+}
+
+// AESKeyFile creates a new AESDecrypter from a key stored in a local file.
+//
+// The file must contain a hex encoded 16, 24 or 32 byte key. Surrounding whitespace, such as a trailing newline, is
+// ignored. "path" will have ~ expanded.
+func AESKeyFile(path string) (*AESDecrypter, error) {
+	data, err := ioutil.ReadFile(ExpandPath(path)) // nolint: gosec
+	if err != nil {
+		return nil, errors.Errorf("failed to read key file: %s", err)
+	}
+	key, err := hex.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil {
+		return nil, errors.Errorf("key file must contain a hex encoded key: %s", err)
+	}
+	return NewAESDecrypter(key)
+}
+
+// Encrypt plaintext into the form accepted by Decrypt().
+func (a *AESDecrypter) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, a.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errors.WithStack(err)
+	}
+	sealed := a.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt ciphertext produced by Encrypt().
+func (a *AESDecrypter) Decrypt(ciphertext string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", errors.Errorf("invalid ciphertext: %s", err)
+	}
+	if len(data) < a.aead.NonceSize() {
+		return "", errors.Errorf("invalid ciphertext: too short")
+	}
+	nonce, sealed := data[:a.aead.NonceSize()], data[a.aead.NonceSize():]
+	plaintext, err := a.aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", errors.Errorf("invalid ciphertext: %s", err)
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,136 @@
+package kong_test
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/alecthomas/kong"
+)
+
+func testAESKeyFile(t *testing.T) (string, *kong.AESDecrypter) {
+	t.Helper()
+	f, err := ioutil.TempFile("", "kong-key-")
+	require.NoError(t, err)
+	_, err = f.WriteString(hex.EncodeToString([]byte("0123456789abcdef0123456789abcdef")) + "\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	decrypter, err := kong.AESKeyFile(f.Name())
+	require.NoError(t, err)
+	return f.Name(), decrypter
+}
+
+func TestAESKeyFile(t *testing.T) {
+	key := "0123456789abcdef0123456789abcdef"
+	tests := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{"Hex", hex.EncodeToString([]byte(key)), ""},
+		{"HexTrailingNewline", hex.EncodeToString([]byte(key)) + "\n", ""},
+		{"HexSurroundingWhitespace", "  " + hex.EncodeToString([]byte(key)) + "\r\n", ""},
+		{"Raw", "a raw key that is 32 bytes long!", "key file must contain a hex encoded key: encoding/hex: invalid byte: U+0020 ' '"},
+		{"RawHexDigits", key, ""}, // Always decoded as hex, ie. a 16 byte key.
+		{"InvalidLength", "abcd\n", "crypto/aes: invalid key size 2"},
+	}
+	for _, test := range tests {
+		// nolint: scopelint
+		t.Run(test.name, func(t *testing.T) {
+			f, err := ioutil.TempFile("", "kong-key-")
+			require.NoError(t, err)
+			defer os.Remove(f.Name())
+			_, err = f.WriteString(test.content)
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+			_, err = kong.AESKeyFile(f.Name())
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDecryptResolvedValues(t *testing.T) {
+	var cli struct {
+		Password string
+		Port     int
+		Plain    string
+	}
+	path, decrypter := testAESKeyFile(t)
+	defer os.Remove(path)
+
+	password, err := decrypter.Encrypt("hunter2")
+	require.NoError(t, err)
+	port, err := decrypter.Encrypt("8080")
+	require.NoError(t, err)
+
+	r, err := kong.JSON(strings.NewReader(`{"password": "enc:` + password + `", "port": "enc:` + port + `", "plain": "plain"}`))
+	require.NoError(t, err)
+	ctx, err := mustNew(t, &cli, kong.Resolvers(r), kong.Decryption("enc:", decrypter)).Parse(nil)
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", cli.Password)
+	require.Equal(t, 8080, cli.Port)
+	require.Equal(t, "plain", cli.Plain)
+
+	secrets := []string{}
+	for _, path := range ctx.Path {
+		if path.Secret {
+			secrets = append(secrets, path.Flag.Name)
+		}
+	}
+	require.Equal(t, []string{"password", "port"}, secrets)
+}
+
+func TestDecryptInvalidCiphertext(t *testing.T) {
+	var cli struct {
+		Password string
+	}
+	path, decrypter := testAESKeyFile(t)
+	defer os.Remove(path)
+
+	r, err := kong.JSON(strings.NewReader(`{"password": "enc:bm90IHZhbGlk"}`))
+	require.NoError(t, err)
+	_, err = mustNew(t, &cli, kong.Resolvers(r), kong.Decryption("enc:", decrypter)).Parse(nil)
+	require.EqualError(t, err, "--password: failed to decrypt value: invalid ciphertext: too short")
+}
+
+func TestDecryptedValuesAreNotLeaked(t *testing.T) {
+	var cli struct {
+		Port int
+	}
+	var resolver kong.ResolverFunc = func(context *kong.Context, parent *kong.Path, flag *kong.Flag) (interface{}, error) {
+		if flag.Name == "port" {
+			return "enc:whatever", nil
+		}
+		return nil, nil
+	}
+	decrypter := kong.DecrypterFunc(func(ciphertext string) (string, error) { return "s3cr3t", nil })
+	_, err := mustNew(t, &cli, kong.Resolvers(resolver), kong.Decryption("enc:", decrypter)).Parse(nil)
+	require.EqualError(t, err, "--port: invalid value <redacted> from encrypted configuration")
+	require.NotContains(t, err.Error(), "s3cr3t")
+}
+
+func TestDecryptedEnumValuesAreNotLeaked(t *testing.T) {
+	var cli struct {
+		Mode string `enum:"a,b" default:"a"`
+	}
+	var resolver kong.ResolverFunc = func(context *kong.Context, parent *kong.Path, flag *kong.Flag) (interface{}, error) {
+		if flag.Name == "mode" {
+			return "enc:whatever", nil
+		}
+		return nil, nil
+	}
+	decrypter := kong.DecrypterFunc(func(ciphertext string) (string, error) { return "TOPSECRET", nil })
+	_, err := mustNew(t, &cli, kong.Resolvers(resolver), kong.Decryption("enc:", decrypter)).Parse(nil)
+	require.EqualError(t, err, `--mode must be one of "a","b" but got <redacted>`)
+
+	_, err = kong.Load(&cli, kong.Resolvers(resolver), kong.Decryption("enc:", decrypter))
+	require.EqualError(t, err, `--mode must be one of "a","b" but got <redacted>`)
+}
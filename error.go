@@ -1,5 +1,10 @@
 package kong
 
+import (
+	"fmt"
+	"strings"
+)
+
 // ParseError is the error type returned by Kong.Parse().
 //
 // It contains the parse Context that triggered the error.
@@ -10,3 +15,15 @@ type ParseError struct {
 
 // Cause returns the original cause of the error.
 func (p *ParseError) Cause() error { return p.error }
+
+// ValidationErrors is returned by Context.Validate() when there are multiple problems with the command-line and
+// errors are aggregated with the AggregateErrors() option.
+type ValidationErrors []error
+
+func (v ValidationErrors) Error() string {
+	lines := []string{fmt.Sprintf("%d problems:", len(v))}
+	for _, err := range v {
+		lines = append(lines, "- "+err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
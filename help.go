@@ -27,6 +27,35 @@ func (h helpValue) BeforeApply(ctx *Context) error {
 	return nil
 }
 
+// Help command.
+type helpCommand struct {
+	Command []string `arg optional help:"Command to show help for."`
+}
+
+func (h *helpCommand) BeforeApply(ctx *Context, trace *Path) error {
+	args := []string{}
+	for _, path := range ctx.Path {
+		if path.Positional != nil && path.Parent == trace.Command {
+			args = ctx.Value(path).Interface().([]string)
+		}
+	}
+	target, err := Trace(ctx.Kong, args)
+	if err != nil {
+		return err
+	}
+	if target.Error != nil {
+		return target.Error
+	}
+	options := ctx.Kong.helpOptions
+	options.Summary = false
+	err = ctx.Kong.help(options, target)
+	if err != nil {
+		return err
+	}
+	ctx.Kong.Exit(0)
+	return nil
+}
+
 // HelpOptions for HelpPrinters.
 type HelpOptions struct {
 	// Don't print top-level usage summary.
@@ -41,6 +70,24 @@ type HelpOptions struct {
 	// Tree writes command chains in a tree structure instead of listing them separately.
 	Tree bool
 
+	// TreeFlags includes the flags of each command in the tree view.
+	TreeFlags bool
+
+	// TreeDepth limits the number of command levels displayed in the tree view. Truncated branches are summarised
+	// as "(N more subcommands)". Zero means unlimited.
+	//
+	// Hidden subcommands are omitted from the tree view if TreeFlags or TreeDepth are set.
+	TreeDepth int
+
+	// Markdown renders help text as Markdown, supporting lists, code blocks, emphasis and links. The default is
+	// to format help text with go/doc.
+	//
+	// Only the terminal rendering is affected, help text in the model is left as-is.
+	Markdown bool
+
+	// Colour enables terminal styling of Markdown help text.
+	Colour bool
+
 	// Indenter modulates the given prefix for the next layer in the tree view.
 	// The following exported templates can be used: kong.SpaceIndenter, kong.LineIndenter, kong.TreeIndenter
 	// The kong.SpaceIndenter will be used by default.
@@ -70,10 +117,28 @@ type HelpValueFormatter func(value *Value) string
 
 // DefaultHelpValueFormatter is the default HelpValueFormatter.
 func DefaultHelpValueFormatter(value *Value) string {
-	if value.Tag.Env == "" {
+	suffixes := []string{}
+	if paired := value.Paired; paired != nil {
+		if paired.Flag != nil {
+			suffixes = append(suffixes, "(or "+paired.ShortSummary()+")")
+		} else {
+			suffixes = append(suffixes, "(or <"+paired.Name+">)")
+		}
+	}
+	switch {
+	case value.Restricted():
+		if value.AllowedSources() == "" {
+			suffixes = append(suffixes, "(not settable)")
+		} else {
+			suffixes = append(suffixes, "(from "+value.AllowedSources()+")")
+		}
+	case value.Tag.Env != "":
+		suffixes = append(suffixes, "($"+value.Tag.Env+")")
+	}
+	if len(suffixes) == 0 {
 		return value.Help
 	}
-	suffix := "($" + value.Tag.Env + ")"
+	suffix := strings.Join(suffixes, " ")
 	switch {
 	case strings.HasSuffix(value.Help, "."):
 		return value.Help[:len(value.Help)-1] + " " + suffix + "."
@@ -105,12 +170,24 @@ func printApp(w *helpWriter, app *Application) {
 	}
 	printNodeDetail(w, app.Node, true)
 	cmds := app.Leaves(true)
-	if len(cmds) > 0 && app.HelpFlag != nil {
-		w.Print("")
-		if w.Summary {
-			w.Printf(`Run "%s --help" for more information.`, app.Name)
-		} else {
-			w.Printf(`Run "%s <command> --help" for more information on a command.`, app.Name)
+	if len(cmds) > 0 {
+		helpFlag := acceptsHelpFlag(app, app.Node)
+		switch {
+		case helpFlag && w.Summary:
+			w.Print("")
+			w.Printf(`Run "%s --%s" for more information.`, app.Name, app.HelpFlag.Name)
+		case helpFlag:
+			w.Print("")
+			w.Printf(`Run "%s <command> --%s" for more information on a command.`, app.Name, app.HelpFlag.Name)
+		case app.HelpCommand != nil:
+			w.Print("")
+			w.Printf(`Run "%s help <command>" for more information on a command.`, app.Name)
+		case app.HelpFlag != nil:
+			// The help flag is restricted to subtrees with HelpFor().
+			w.Print("")
+			for _, node := range helpFlagNodes(app) {
+				w.Printf(`Run "%s --%s" for more information on %s.`, node.FullPath(), app.HelpFlag.Name, node.Path())
+			}
 		}
 	}
 }
@@ -120,10 +197,47 @@ func printCommand(w *helpWriter, app *Application, cmd *Command) {
 		w.Printf("Usage: %s %s", app.Name, cmd.Summary())
 	}
 	printNodeDetail(w, cmd, true)
-	if w.Summary && app.HelpFlag != nil {
-		w.Print("")
-		w.Printf(`Run "%s --help" for more information.`, cmd.FullPath())
+	if w.Summary {
+		switch {
+		case acceptsHelpFlag(app, cmd):
+			w.Print("")
+			w.Printf(`Run "%s --%s" for more information.`, cmd.FullPath(), app.HelpFlag.Name)
+		case app.HelpCommand != nil:
+			w.Print("")
+			w.Printf(`Run "%s help %s" for more information.`, app.Name, cmd.Path())
+		}
+	}
+}
+
+// Returns true if the help flag is accepted by node, which may not be the case if it is restricted with HelpFor().
+func acceptsHelpFlag(app *Application, node *Node) bool {
+	if app.HelpFlag == nil {
+		return false
 	}
+	for _, group := range node.AllFlags(false) {
+		for _, flag := range group {
+			if flag == app.HelpFlag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// The nodes the help flag was added to by HelpFor().
+func helpFlagNodes(app *Application) (out []*Node) {
+	_ = Visit(app.Node, func(node Visitable, next Next) error {
+		if n, ok := node.(*Node); ok {
+			for _, flag := range n.Flags {
+				if flag == app.HelpFlag {
+					out = append(out, n)
+					return nil
+				}
+			}
+		}
+		return next(nil)
+	})
+	return out
 }
 
 func printNodeDetail(w *helpWriter, node *Node, hide bool) {
@@ -279,9 +393,15 @@ func (h *helpWriter) Write(w io.Writer) error {
 }
 
 func (h *helpWriter) Wrap(text string) {
-	w := bytes.NewBuffer(nil)
-	doc.ToText(w, strings.TrimSpace(text), "", "    ", h.width)
-	for _, line := range strings.Split(strings.TrimSpace(w.String()), "\n") {
+	var lines []string
+	if h.Markdown {
+		lines = renderMarkdown(text, h.width, h.Colour)
+	} else {
+		w := bytes.NewBuffer(nil)
+		doc.ToText(w, strings.TrimSpace(text), "", "    ", h.width)
+		lines = strings.Split(strings.TrimSpace(w.String()), "\n")
+	}
+	for _, line := range lines {
 		h.Print(line)
 	}
 }
@@ -334,9 +454,17 @@ func writeTwoColumns(w *helpWriter, rows [][2]string) {
 	offsetStr := strings.Repeat(" ", leftSize+defaultColumnPadding)
 
 	for _, row := range rows {
-		buf := bytes.NewBuffer(nil)
-		doc.ToText(buf, row[1], "", strings.Repeat(" ", defaultIndent), w.width-leftSize-defaultColumnPadding)
-		lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+		var lines []string
+		if w.Markdown {
+			lines = renderMarkdown(row[1], w.width-leftSize-defaultColumnPadding, w.Colour)
+		} else {
+			buf := bytes.NewBuffer(nil)
+			doc.ToText(buf, row[1], "", strings.Repeat(" ", defaultIndent), w.width-leftSize-defaultColumnPadding)
+			lines = strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+		}
+		if len(lines) == 0 {
+			lines = []string{""}
+		}
 
 		line := fmt.Sprintf("%-*s", leftSize, row[0])
 		if len(row[0]) < maxLeft {
@@ -355,6 +483,28 @@ func formatFlag(haveShort bool, flag *Flag) string {
 	flagString := ""
 	name := flag.Name
 	isBool := flag.IsBool()
+	// Flags that can't be set on the command-line are listed by their environment variable, or bare name.
+	if flag.Tag.NoCLI {
+		if flag.Tag.Env != "" && !flag.Tag.NoEnv {
+			flagString = "$" + flag.Tag.Env
+		} else {
+			flagString = name
+		}
+		if haveShort {
+			flagString = "    " + flagString
+		}
+		if !isBool {
+			flagString += "=" + flag.FormatPlaceHolder()
+		}
+		return flagString
+	}
+	if flag.ShortOnly {
+		flagString += fmt.Sprintf("-%c", flag.Short)
+		if !isBool {
+			flagString += " " + flag.FormatPlaceHolder()
+		}
+		return flagString
+	}
 	if flag.Short != 0 {
 		flagString += fmt.Sprintf("-%c, --%s", flag.Short, name)
 	} else {
@@ -365,13 +515,19 @@ func formatFlag(haveShort bool, flag *Flag) string {
 		}
 	}
 	if !isBool {
-		flagString += fmt.Sprintf("=%s", flag.FormatPlaceHolder())
+		flagString += flag.valueSeparator() + flag.FormatPlaceHolder()
+	} else {
+		flagString += boolValuesHint(flag)
 	}
 	return flagString
 }
 
 // CommandTree creates a tree with the given node name as root and its children's arguments and sub commands as leaves.
 func (h *HelpOptions) CommandTree(node *Node, prefix string) (rows [][2]string) {
+	return h.commandTree(node, prefix, 1)
+}
+
+func (h *HelpOptions) commandTree(node *Node, prefix string, depth int) (rows [][2]string) {
 	var nodeName string
 	switch node.Type {
 	default:
@@ -385,15 +541,46 @@ func (h *HelpOptions) CommandTree(node *Node, prefix string) (rows [][2]string)
 	} else {
 		prefix = h.Indenter(prefix)
 	}
+	if h.TreeFlags {
+		for _, flag := range node.Flags {
+			if !flag.Hidden {
+				rows = append(rows, [2]string{prefix + formatFlag(false, flag), flag.Help})
+			}
+		}
+	}
 	for _, arg := range node.Positional {
 		rows = append(rows, [2]string{prefix + arg.Summary(), arg.Help})
 	}
+	if h.TreeDepth > 0 && depth >= h.TreeDepth {
+		switch count := countSubcommands(node); count {
+		case 0:
+		case 1:
+			rows = append(rows, [2]string{prefix + "(1 more subcommand)", ""})
+		default:
+			rows = append(rows, [2]string{prefix + fmt.Sprintf("(%d more subcommands)", count), ""})
+		}
+		return
+	}
 	for _, subCmd := range node.Children {
-		rows = append(rows, h.CommandTree(subCmd, prefix)...)
+		if subCmd.Hidden && (h.TreeFlags || h.TreeDepth > 0) {
+			continue
+		}
+		rows = append(rows, h.commandTree(subCmd, prefix, depth+1)...)
 	}
 	return
 }
 
+// Count all visible commands and branching arguments below node.
+func countSubcommands(node *Node) int {
+	count := 0
+	for _, child := range node.Children {
+		if !child.Hidden {
+			count += 1 + countSubcommands(child)
+		}
+	}
+	return count
+}
+
 // SpaceIndenter adds a space indent to the given prefix.
 func SpaceIndenter(prefix string) string {
 	return prefix + strings.Repeat(" ", defaultIndent)
@@ -67,13 +67,13 @@ func TestHelp(t *testing.T) {
 A test app.
 
 Flags:
-  --help                 Show context-sensitive help.
-  --string=STRING        A string flag.
-  --bool                 A bool flag with very long help that wraps a lot and is
-                         verbose and is really verbose.
-  --slice=STR,...        A slice of strings.
-  --map=KEY=VALUE;...    A map of strings to ints.
-  --required             A required flag.
+  -h, --help                 Show context-sensitive help.
+      --string=STRING        A string flag.
+      --bool                 A bool flag with very long help that wraps a lot
+                             and is verbose and is really verbose.
+      --slice=STR,...        A slice of strings.
+      --map=KEY=VALUE;...    A map of strings to ints.
+      --required             A required flag.
 
 Commands:
   one --required
@@ -107,18 +107,18 @@ Sub-sub-arg.
 Detailed help provided through the HelpProvider interface.
 
 Flags:
-  --help                 Show context-sensitive help.
-  --string=STRING        A string flag.
-  --bool                 A bool flag with very long help that wraps a lot and is
-                         verbose and is really verbose.
-  --slice=STR,...        A slice of strings.
-  --map=KEY=VALUE;...    A map of strings to ints.
-  --required             A required flag.
-
-  --flag=STRING          Nested flag under two.
-  --required-two
-
-  --required-three
+  -h, --help                 Show context-sensitive help.
+      --string=STRING        A string flag.
+      --bool                 A bool flag with very long help that wraps a lot
+                             and is verbose and is really verbose.
+      --slice=STR,...        A slice of strings.
+      --map=KEY=VALUE;...    A map of strings to ints.
+      --required             A required flag.
+
+      --flag=STRING          Nested flag under two.
+      --required-two
+
+      --required-three
 `
 		t.Log(expected)
 		t.Log(w.String())
@@ -173,7 +173,7 @@ func TestHelpTree(t *testing.T) {
 A test app.
 
 Flags:
-  --help    Show context-sensitive help.
+  -h, --help    Show context-sensitive help.
 
 Commands:
   one          subcommand one
@@ -206,7 +206,7 @@ Run "test-app <command> --help" for more information on a command.
 subcommand one
 
 Flags:
-  --help    Show context-sensitive help.
+  -h, --help    Show context-sensitive help.
 
 Commands:
   thing      subcommand thing
@@ -232,6 +232,114 @@ func TestEnvarAutoHelp(t *testing.T) {
 	require.Contains(t, w.String(), "A flag ($FLAG).")
 }
 
+func TestShortOnlyHelp(t *testing.T) {
+	var cli struct {
+		Verbose bool   `short:"v" shortonly:"" help:"Be verbose."`
+		Output  string `short:"o" shortonly:"" placeholder:"FILE" help:"Output file."`
+	}
+	w := &strings.Builder{}
+	p := mustNew(t, &cli, kong.Writers(w, w), kong.Exit(func(int) {}))
+	_, err := p.Parse([]string{"--help"})
+	require.NoError(t, err)
+	expected := `Usage: test
+
+Flags:
+  -h, --help    Show context-sensitive help.
+  -v            Be verbose.
+  -o FILE       Output file.
+`
+	require.Equal(t, expected, w.String())
+}
+
+func TestBoolValuesHelp(t *testing.T) {
+	var cli struct {
+		Verbose bool         `help:"Be verbose."`
+		Cache   bool         `boolvalues:"on/off" help:"Use the cache."`
+		Colour  kong.TriBool `help:"Colourise output."`
+	}
+	w := &strings.Builder{}
+	p := mustNew(t, &cli, kong.Writers(w, w), kong.Exit(func(int) {}))
+	_, err := p.Parse([]string{"--help"})
+	require.NoError(t, err)
+	expected := `Usage: test
+
+Flags:
+  -h, --help              Show context-sensitive help.
+      --verbose           Be verbose.
+      --cache[=on|off]    Use the cache.
+      --colour[=true|false|auto]
+                          Colourise output.
+`
+	require.Equal(t, expected, w.String())
+}
+
+func TestRestrictedSourcesHelp(t *testing.T) {
+	var cli struct {
+		Token  string `env:"TOKEN" nocli:"" help:"API token."`
+		Region string `env:"REGION" noconfig:"" help:"Region."`
+		Name   string `noenv:"" noconfig:""`
+	}
+	w := &strings.Builder{}
+	p := mustNew(t, &cli, kong.Writers(w, w), kong.Exit(func(int) {}))
+	_, err := p.Parse([]string{"--help"})
+	require.NoError(t, err)
+	expected := `Usage: test
+
+Flags:
+  -h, --help             Show context-sensitive help.
+      $TOKEN=STRING      API token (from $TOKEN or configuration).
+      --region=STRING    Region (from the command-line or $REGION).
+      --name=STRING      (from the command-line)
+`
+	require.Equal(t, expected, w.String())
+}
+
+func TestArityHelp(t *testing.T) {
+	var cli struct {
+		Point  [2]int `arity:"2" help:"A point."`
+		Rename struct {
+			Old string
+			New string
+		} `arity:"2" help:"Rename a file."`
+		Size []int `arity:"2" placeholder:"W H" help:"Size."`
+	}
+	w := &strings.Builder{}
+	p := mustNew(t, &cli, kong.Writers(w, w), kong.Exit(func(int) {}))
+	_, err := p.Parse([]string{"--help"})
+	require.NoError(t, err)
+	expected := `Usage: test
+
+Flags:
+  -h, --help              Show context-sensitive help.
+      --point INT INT     A point.
+      --rename OLD NEW    Rename a file.
+      --size W H          Size.
+`
+	require.Equal(t, expected, w.String())
+}
+
+func TestPositionalOrFlagHelp(t *testing.T) {
+	var cli struct {
+		Name string `arg:"" flag:"" help:"Name to get."`
+	}
+	w := &strings.Builder{}
+	p := mustNew(t, &cli, kong.Writers(w, w), kong.Exit(func(int) { panic(true) }))
+	require.PanicsWithValue(t, true, func() {
+		_, err := p.Parse([]string{"--help"})
+		require.NoError(t, err)
+	})
+	expected := `Usage: test <name>
+
+Arguments:
+  <name>    Name to get (or --name).
+
+Flags:
+  -h, --help           Show context-sensitive help.
+      --name=STRING    Name to get (or <name>).
+`
+	require.Equal(t, expected, w.String())
+}
+
 func TestCustomHelpFormatter(t *testing.T) {
 	var cli struct {
 		Flag string `env:"FLAG" help:"A flag."`
@@ -248,3 +356,230 @@ func TestCustomHelpFormatter(t *testing.T) {
 	require.NoError(t, err)
 	require.Contains(t, w.String(), "A flag.")
 }
+
+func TestConfigureHelpFlag(t *testing.T) {
+	var cli struct {
+		Host string `short:"h" help:"Host to connect to."`
+	}
+	w := bytes.NewBuffer(nil)
+	exited := false
+	app := mustNew(t, &cli,
+		kong.Writers(w, w),
+		kong.ConfigureHelpFlag("usage", '?'),
+		kong.Exit(func(int) {
+			exited = true
+			panic(true) // Panic to fake "exit".
+		}),
+	)
+	_, err := app.Parse([]string{"-h", "example.com"})
+	require.NoError(t, err)
+	require.Equal(t, "example.com", cli.Host)
+
+	require.PanicsWithValue(t, true, func() {
+		_, err := app.Parse([]string{"-?"})
+		require.NoError(t, err)
+	})
+	require.True(t, exited)
+	require.Contains(t, w.String(), "-?, --usage")
+
+	_, err = app.Parse([]string{"--help"})
+	require.EqualError(t, err, "unknown flag --help")
+}
+
+func TestHelpCommand(t *testing.T) {
+	// nolint: govet
+	var cli struct {
+		Required bool `required`
+		Server   struct {
+			Logs struct {
+				Follow bool `help:"Follow the logs."`
+			} `cmd help:"Show server logs."`
+		} `cmd help:"Server commands."`
+	}
+	w := bytes.NewBuffer(nil)
+	exited := false
+	app := mustNew(t, &cli,
+		kong.Name("test-app"),
+		kong.Writers(w, w),
+		kong.NoDefaultHelp(),
+		kong.HelpCommand(),
+		kong.Exit(func(int) {
+			exited = true
+			panic(true) // Panic to fake "exit".
+		}),
+	)
+	require.NotNil(t, app.Model.HelpCommand)
+
+	t.Run("Full", func(t *testing.T) {
+		require.PanicsWithValue(t, true, func() {
+			_, err := app.Parse([]string{"help"})
+			require.NoError(t, err)
+		})
+		require.True(t, exited)
+		require.Contains(t, w.String(), "Usage: test-app --required <command>")
+		require.Contains(t, w.String(), `Run "test-app help <command>" for more information on a command.`)
+	})
+
+	t.Run("Selected", func(t *testing.T) {
+		exited = false
+		w.Truncate(0)
+		require.PanicsWithValue(t, true, func() {
+			_, err := app.Parse([]string{"help", "server", "logs"})
+			require.NoError(t, err)
+		})
+		require.True(t, exited)
+		require.Contains(t, w.String(), "Usage: test-app server logs --required")
+		require.Contains(t, w.String(), "Follow the logs.")
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		_, err := app.Parse([]string{"help", "sever"})
+		require.EqualError(t, err, "unexpected argument sever, did you mean \"server\"?")
+	})
+}
+
+func TestHelpFor(t *testing.T) {
+	var cli struct {
+		Server struct {
+			Logs struct{} `cmd`
+		} `cmd`
+		Client struct{} `cmd`
+	}
+	w := bytes.NewBuffer(nil)
+	exited := false
+	app := mustNew(t, &cli,
+		kong.Writers(w, w),
+		kong.HelpFor(&cli.Server),
+		kong.Exit(func(int) {
+			exited = true
+			panic(true) // Panic to fake "exit".
+		}),
+	)
+	require.PanicsWithValue(t, true, func() {
+		_, err := app.Parse([]string{"server", "logs", "--help"})
+		require.NoError(t, err)
+	})
+	require.True(t, exited)
+
+	_, err := app.Parse([]string{"client", "--help"})
+	require.EqualError(t, err, "unknown flag --help")
+	_, err = app.Parse([]string{"--help"})
+	require.EqualError(t, err, "unknown flag --help")
+
+	// Root help must not suggest a help flag that isn't accepted there.
+	w.Reset()
+	ctx, err := kong.Trace(app, nil)
+	require.NoError(t, err)
+	err = kong.DefaultHelpPrinter(kong.HelpOptions{}, ctx)
+	require.NoError(t, err)
+	require.NotContains(t, w.String(), "<command> --help")
+	require.Contains(t, w.String(), `Run "test server --help" for more information on server.`)
+}
+
+func TestHelpForWithHelpCommand(t *testing.T) {
+	var cli struct {
+		Server struct{} `cmd`
+		Client struct{} `cmd`
+	}
+	w := bytes.NewBuffer(nil)
+	app := mustNew(t, &cli, kong.Writers(w, w), kong.HelpFor(&cli.Server), kong.HelpCommand())
+	ctx, err := kong.Trace(app, nil)
+	require.NoError(t, err)
+	err = kong.DefaultHelpPrinter(kong.HelpOptions{}, ctx)
+	require.NoError(t, err)
+	require.Contains(t, w.String(), `Run "test help <command>" for more information on a command.`)
+}
+
+func TestHelpTreeFlagsAndDepth(t *testing.T) {
+	// nolint: govet
+	var cli struct {
+		One struct {
+			Verbose bool `help:"Be verbose."`
+			Thing   struct {
+				Deep struct {
+				} `cmd help:"deep command"`
+				Deeper struct {
+				} `cmd help:"deeper command"`
+				Secret struct {
+				} `cmd hidden`
+			} `cmd help:"subcommand thing"`
+			Hidden struct {
+			} `cmd hidden`
+		} `cmd help:"subcommand one"`
+
+		Two struct {
+			Name string `help:"A name."`
+			Arg  string `arg help:"argument"`
+		} `cmd help:"Another subcommand."`
+	}
+
+	w := bytes.NewBuffer(nil)
+	app := mustNew(t, &cli,
+		kong.Name("test-app"),
+		kong.Writers(w, w),
+		kong.ConfigureHelp(kong.HelpOptions{
+			Tree:      true,
+			TreeFlags: true,
+			TreeDepth: 2,
+			Indenter:  kong.LineIndenter,
+		}),
+		kong.Exit(func(int) {
+			panic(true) // Panic to fake "exit".
+		}),
+	)
+	require.PanicsWithValue(t, true, func() {
+		_, err := app.Parse([]string{"--help"})
+		require.NoError(t, err)
+	})
+	expected := `Usage: test-app <command>
+
+Flags:
+  -h, --help    Show context-sensitive help.
+
+Commands:
+  one                         subcommand one
+  - --verbose                 Be verbose.
+  - thing                     subcommand thing
+    - (2 more subcommands)
+
+  two                         Another subcommand.
+  - --name=STRING             A name.
+  - <arg>                     argument
+
+Run "test-app <command> --help" for more information on a command.
+`
+	require.Equal(t, expected, w.String())
+}
+
+func TestMarkdownHelp(t *testing.T) {
+	var cli struct {
+		Mode string `help:"Mode to run in, **fast** or **safe**."`
+	}
+	w := bytes.NewBuffer(nil)
+	app := mustNew(t, &cli,
+		kong.Name("test-app"),
+		kong.Description("Run the *test* app. See [docs](https://example.com).\n\n- first\n- second"),
+		kong.Writers(w, w),
+		kong.ConfigureHelp(kong.HelpOptions{Markdown: true}),
+		kong.Exit(func(int) {
+			panic(true) // Panic to fake "exit".
+		}),
+	)
+	require.PanicsWithValue(t, true, func() {
+		_, err := app.Parse([]string{"--help"})
+		require.NoError(t, err)
+	})
+	expected := `Usage: test-app
+
+Run the test app. See docs (https://example.com).
+
+- first
+- second
+
+Flags:
+  -h, --help           Show context-sensitive help.
+      --mode=STRING    Mode to run in, fast or safe.
+`
+	require.Equal(t, expected, w.String())
+	require.Equal(t, "Run the *test* app. See [docs](https://example.com).\n\n- first\n- second", app.Model.Help)
+}
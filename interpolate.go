@@ -7,10 +7,22 @@ import (
 
 var interpolationRegex = regexp.MustCompile(`((?:\${([[:alpha:]_][[:word:]]*))(?:=([^}]+))?})|(\$)|([^$]+)`)
 
+// As interpolationRegex, but also matching "$$" as an escaped "$".
+var escapedInterpolationRegex = regexp.MustCompile(`\$\$|` + interpolationRegex.String())
+
 // Interpolate variables from vars into s for substrings in the form ${var} or ${var=default}.
 func interpolate(s string, vars Vars, updatedVars map[string]string) (string, error) {
+	return interpolateMatches(interpolationRegex, s, vars, updatedVars)
+}
+
+// Interpolate variables from vars into s as with interpolate(), except that "$$" is an escaped "$".
+func interpolateEscaped(s string, vars Vars) (string, error) {
+	return interpolateMatches(escapedInterpolationRegex, s, vars, nil)
+}
+
+func interpolateMatches(re *regexp.Regexp, s string, vars Vars, updatedVars map[string]string) (string, error) {
 	out := ""
-	matches := interpolationRegex.FindAllStringSubmatch(s, -1)
+	matches := re.FindAllStringSubmatch(s, -1)
 	if len(matches) == 0 {
 		return s, nil
 	}
@@ -31,6 +43,8 @@ func interpolate(s string, vars Vars, updatedVars map[string]string) (string, er
 				value = match[3]
 			}
 			out += value
+		} else if match[0] == "$$" {
+			out += "$"
 		} else {
 			out += match[0]
 		}
@@ -17,3 +17,31 @@ func TestInterpolate(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, `Bobby Brown is 35 years old and 180 cm tall`, actual)
 }
+
+func TestInterpolateEscaped(t *testing.T) {
+	actual, err := interpolateEscaped("$${name} costs $$${price}", Vars{"name": "kong", "price": "10"})
+	require.NoError(t, err)
+	require.Equal(t, "${name} costs $10", actual)
+}
+
+func TestInterpolateAdjacentEscapes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"$$${var}", "$value"},
+		{"$$$$", "$$"},
+		{"$$$", "$$"},
+		{"$$$$${var}", "$$value"},
+		{"${var}$$${var}", "value$value"},
+		{"${missing=a$$b}", "a$$b"},
+	}
+	for _, test := range tests {
+		// nolint: scopelint
+		t.Run(test.input, func(t *testing.T) {
+			actual, err := interpolateEscaped(test.input, Vars{"var": "value"})
+			require.NoError(t, err)
+			require.Equal(t, test.expected, actual)
+		})
+	}
+}
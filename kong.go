@@ -7,6 +7,9 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
 )
 
 var (
@@ -42,21 +45,34 @@ type Kong struct {
 	Stdout io.Writer
 	Stderr io.Writer
 
-	bindings  bindings
-	loader    ConfigurationLoader
-	resolvers []Resolver
-	registry  *Registry
+	bindings        bindings
+	loader          ConfigurationLoader
+	resolvers       []Resolver
+	decrypters      []prefixedDecrypter
+	expandResolved  bool
+	migrations      *configMigrations
+	aggregateErrors bool
+	inheritance     map[string]TagInheritance
+	registry        *Registry
 
 	noDefaultHelp bool
+	helpFlagName  string
+	helpFlagShort rune
+	helpCommand   bool
 	usageOnError  bool
 	help          HelpPrinter
 	helpFormatter HelpValueFormatter
 	helpOptions   HelpOptions
 	helpFlag      *Flag
 	vars          Vars
+	argsEnv       *string
+	runOrder      RunOrder
+	preprocessors []Preprocessor
 
 	// Set temporarily by Options. These are applied after build().
 	postBuildOptions []Option
+	// Configuration files loaded by Configuration(). ConfigMigrations are applied to these after postBuildOptions.
+	configFiles []configFile
 }
 
 // New creates a new Kong parser on grammar.
@@ -71,6 +87,8 @@ func New(grammar interface{}, options ...Option) (*Kong, error) {
 		vars:          Vars{},
 		bindings:      bindings{},
 		helpFormatter: DefaultHelpValueFormatter,
+		helpFlagName:  "help",
+		helpFlagShort: 'h',
 	}
 
 	options = append(options, Bind(k))
@@ -100,6 +118,23 @@ func New(grammar interface{}, options ...Option) (*Kong, error) {
 	}
 	k.postBuildOptions = nil
 
+	if k.argsEnv != nil {
+		name := k.envArgsName()
+		_ = Visit(k.Model, func(node Visitable, next Next) error {
+			if value, ok := node.(*Value); ok {
+				value.envArgs = name
+			}
+			return next(nil)
+		})
+	}
+
+	for _, file := range k.configFiles {
+		if err = k.applyConfigMigrations(file.path, file.resolver); err != nil {
+			return nil, err
+		}
+	}
+	k.configFiles = nil
+
 	if err = k.interpolate(k.Model.Node); err != nil {
 		return nil, err
 	}
@@ -132,6 +167,11 @@ func (k *Kong) interpolate(node *Node) (err error) {
 			if err != nil {
 				return fmt.Errorf("help for %s: %s", node.Path(), err)
 			}
+			for i, file := range node.Tag.ConfigFiles {
+				if node.Tag.ConfigFiles[i], err = interpolate(file, vars, nil); err != nil {
+					return fmt.Errorf("configfiles for %s: %s", node.Path(), err)
+				}
+			}
 			err = next(nil)
 			stack.pop()
 			return err
@@ -171,9 +211,9 @@ func (k *Kong) extraFlags() []*Flag {
 	var helpTarget helpValue
 	value := reflect.ValueOf(&helpTarget).Elem()
 	helpFlag := &Flag{
-		Short: 'h',
+		Short: k.helpFlagShort,
 		Value: &Value{
-			Name:         "help",
+			Name:         k.helpFlagName,
 			Help:         "Show context-sensitive help.",
 			Target:       value,
 			Tag:          &Tag{},
@@ -195,7 +235,11 @@ func (k *Kong) extraFlags() []*Flag {
 // invalid one, which will report a normal error).
 func (k *Kong) Parse(args []string) (ctx *Context, err error) {
 	defer catch(&err)
-	ctx, err = Trace(k, args)
+	envArgs := 0
+	if args, envArgs, err = k.prependEnvArgs(args); err != nil {
+		return nil, err
+	}
+	ctx, err = trace(k, args, envArgs)
 	if err != nil {
 		return nil, err
 	}
@@ -208,6 +252,9 @@ func (k *Kong) Parse(args []string) (ctx *Context, err error) {
 	if err = k.applyHook(ctx, "BeforeResolve"); err != nil {
 		return nil, &ParseError{error: err, Context: ctx}
 	}
+	if err = ctx.addCommandConfigs(); err != nil {
+		return nil, &ParseError{error: err, Context: ctx}
+	}
 	if err = ctx.Resolve(); err != nil {
 		return nil, &ParseError{error: err, Context: ctx}
 	}
@@ -226,6 +273,57 @@ func (k *Kong) Parse(args []string) (ctx *Context, err error) {
 	return ctx, nil
 }
 
+// Policies for inheriting tag keys from embedded structs, combining builtin keys with those registered with
+// InheritTags().
+func (k *Kong) tagInheritance() map[string]TagInheritance {
+	policies := map[string]TagInheritance{}
+	for key, policy := range builtinTagInheritance {
+		policies[key] = policy
+	}
+	for key, policy := range k.inheritance {
+		policies[key] = policy
+	}
+	return policies
+}
+
+// Name of the environment variable configured with EnvArgs().
+func (k *Kong) envArgsName() string {
+	if k.argsEnv == nil || *k.argsEnv != "" {
+		return *k.argsEnv
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, k.Model.Name) + "_OPTS"
+}
+
+// Prepend arguments from the environment variable configured with EnvArgs(), if any.
+//
+// Returns the combined arguments and the number of arguments from the environment.
+func (k *Kong) prependEnvArgs(args []string) ([]string, int, error) {
+	if k.argsEnv == nil {
+		return args, 0, nil
+	}
+	name := k.envArgsName()
+	value := os.Getenv(name)
+	if strings.TrimSpace(value) == "" {
+		return args, 0, nil
+	}
+	envArgs, err := splitShellArgs(value)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "$%s", name)
+	}
+	// "--" would turn every argument on the command-line into a positional argument.
+	for _, arg := range envArgs {
+		if arg == "--" {
+			return nil, 0, errors.Errorf("$%s: \"--\" is not allowed", name)
+		}
+	}
+	return append(envArgs, args...), len(envArgs), nil
+}
+
 func (k *Kong) applyHook(ctx *Context, name string) error {
 	for _, trace := range ctx.Path {
 		var value reflect.Value
@@ -341,14 +439,24 @@ func (k *Kong) FatalIfErrorf(err error, args ...interface{}) {
 
 // LoadConfig from path using the loader configured via Configuration(loader).
 //
-// "path" will have ~ and any variables expanded.
+// Any migrations registered with ConfigMigrations are applied to the loaded configuration.
 func (k *Kong) LoadConfig(path string) (Resolver, error) {
-	var err error
-	path = ExpandPath(path)
-	path, err = interpolate(path, k.vars, nil)
+	resolver, err := k.openConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	if err = k.applyConfigMigrations(path, resolver); err != nil {
+		return nil, err
+	}
+	return resolver, nil
+}
+
+func (k *Kong) openConfig(path string) (Resolver, error) {
+	path, err := interpolate(path, k.vars, nil)
 	if err != nil {
 		return nil, err
 	}
+	path = ExpandPath(path)
 	r, err := os.Open(path) // nolint: gas
 	if err != nil {
 		return nil, err
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 
 	"github.com/alecthomas/kong"
@@ -430,6 +431,74 @@ func TestShort(t *testing.T) {
 	require.Equal(t, "hello", cli.String)
 }
 
+func TestShortOnly(t *testing.T) {
+	var cli struct {
+		Verbose bool   `short:"v" shortonly:""`
+		Output  string `short:"o" shortonly:""`
+	}
+	app := mustNew(t, &cli)
+	_, err := app.Parse([]string{"-v", "-o", "out.txt"})
+	require.NoError(t, err)
+	require.True(t, cli.Verbose)
+	require.Equal(t, "out.txt", cli.Output)
+
+	_, err = app.Parse([]string{"--verbose"})
+	require.EqualError(t, err, "unknown flag --verbose")
+
+	_, err = app.Parse([]string{"-o"})
+	require.EqualError(t, err, "-o: expected string value but got \"EOL\" (<EOL>)")
+}
+
+func TestShortOnlyResolvesByFieldName(t *testing.T) {
+	var cli struct {
+		Output string `short:"o" shortonly:""`
+	}
+	r, err := kong.JSON(strings.NewReader(`{"output": "out.txt"}`))
+	require.NoError(t, err)
+	_, err = mustNew(t, &cli, kong.Resolvers(r)).Parse(nil)
+	require.NoError(t, err)
+	require.Equal(t, "out.txt", cli.Output)
+}
+
+func TestShortOnlyRequiresShort(t *testing.T) {
+	var cli struct {
+		Verbose bool `shortonly:""`
+	}
+	_, err := kong.New(&cli)
+	require.Error(t, err)
+}
+
+func TestDuplicateShortFlag(t *testing.T) {
+	var cli struct {
+		Verbose bool `short:"v"`
+		Version bool `short:"v"`
+	}
+	_, err := kong.New(&cli)
+	require.EqualError(t, err, "duplicate short flag -v")
+}
+
+func TestShortFlagReusedByChild(t *testing.T) {
+	var cli struct {
+		Verbose bool `short:"v"`
+		Cmd     struct {
+			Version bool `short:"v"`
+		} `cmd:""`
+	}
+	_, err := kong.New(&cli)
+	require.NoError(t, err)
+}
+
+func TestShortFlagReplacesHelpShort(t *testing.T) {
+	var cli struct {
+		Host string `short:"h"`
+	}
+	p := mustNew(t, &cli)
+	require.Equal(t, rune(0), p.Model.HelpFlag.Short)
+	_, err := p.Parse([]string{"-h", "localhost"})
+	require.NoError(t, err)
+	require.Equal(t, "localhost", cli.Host)
+}
+
 func TestDuplicateFlagChoosesLast(t *testing.T) {
 	var cli struct {
 		Flag int
@@ -554,6 +623,92 @@ func TestRun(t *testing.T) {
 	require.Equal(t, "argping", cli.Three.SubCommand.Arg)
 }
 
+type runOrderLog []string
+
+type runOrderLeaf struct {
+	Stop string `optional:"" arg:""`
+}
+
+func (r *runOrderLeaf) Run(log *runOrderLog) error {
+	*log = append(*log, "leaf")
+	return nil
+}
+
+type runOrderParent struct {
+	Leaf runOrderLeaf `cmd:""`
+}
+
+func (r *runOrderParent) Run(log *runOrderLog, leaf *runOrderLeaf) error {
+	*log = append(*log, "parent")
+	switch leaf.Stop {
+	case "parent":
+		return kong.ErrStopPropagation
+	case "wrapped":
+		return errors.Wrap(kong.ErrStopPropagation, "parent")
+	}
+	return nil
+}
+
+type runOrderRoot struct {
+	Parent runOrderParent `cmd:""`
+}
+
+func (r *runOrderRoot) Run(log *runOrderLog) error {
+	*log = append(*log, "root")
+	return nil
+}
+
+func TestRunOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []kong.Option
+		args     []string
+		expected runOrderLog
+	}{
+		{"Default", nil, []string{"parent", "leaf"}, runOrderLog{"leaf", "parent", "root"}},
+		{"LeafFirst", []kong.Option{kong.RunOrdering(kong.LeafFirst)}, []string{"parent", "leaf"}, runOrderLog{"leaf", "parent", "root"}},
+		{"RootFirst", []kong.Option{kong.RunOrdering(kong.RootFirst)}, []string{"parent", "leaf"}, runOrderLog{"root", "parent", "leaf"}},
+		{"LeafOnly", []kong.Option{kong.RunOrdering(kong.LeafOnly)}, []string{"parent", "leaf"}, runOrderLog{"leaf"}},
+		{"StopPropagation", []kong.Option{kong.RunOrdering(kong.RootFirst)}, []string{"parent", "leaf", "parent"}, runOrderLog{"root", "parent"}},
+		{"StopPropagationLeafFirst", nil, []string{"parent", "leaf", "parent"}, runOrderLog{"leaf", "parent"}},
+		{"StopPropagationWrapped", nil, []string{"parent", "leaf", "wrapped"}, runOrderLog{"leaf", "parent"}},
+	}
+	for _, test := range tests {
+		// nolint: scopelint
+		t.Run(test.name, func(t *testing.T) {
+			cli := &runOrderRoot{}
+			ctx, err := mustNew(t, cli, test.options...).Parse(test.args)
+			require.NoError(t, err)
+			log := runOrderLog{}
+			err = ctx.Run(&log)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, log)
+		})
+	}
+}
+
+func TestRunLeafOnlyRequiresRun(t *testing.T) {
+	var cli struct {
+		Cmd struct{} `cmd:""`
+	}
+	ctx, err := mustNew(t, &cli, kong.RunOrdering(kong.LeafOnly)).Parse([]string{"cmd"})
+	require.NoError(t, err)
+	err = ctx.Run()
+	require.EqualError(t, err, "no Run() method found on cmd")
+}
+
+func TestRunNodeLeafOnlyNamesNode(t *testing.T) {
+	var cli struct {
+		Parent struct {
+			Leaf runOrderLeaf `cmd:""`
+		} `cmd:""`
+	}
+	ctx, err := mustNew(t, &cli, kong.RunOrdering(kong.LeafOnly)).Parse([]string{"parent", "leaf"})
+	require.NoError(t, err)
+	err = ctx.RunNode(ctx.Selected().Parent, &runOrderLog{})
+	require.EqualError(t, err, "no Run() method found on parent <command>")
+}
+
 func TestInterpolationIntoModel(t *testing.T) {
 	var cli struct {
 		Flag    string `default:"${default}" help:"Help, I need ${somebody}" enum:"${enum}"`
@@ -782,6 +937,142 @@ func TestXorChild(t *testing.T) {
 	require.Error(t, err, "--two and --three can't be used together")
 }
 
+type aggregateCLI struct {
+	Level   string `enum:"debug,info" default:"info"`
+	Name    string `required:""`
+	Colour  bool   `xor:"output"`
+	Plain   bool   `xor:"output"`
+	JSON    bool   `xor:"output"`
+	Command struct {
+		Token string `required:""`
+		Arg   string `arg:""`
+	} `cmd:""`
+}
+
+func TestAggregateErrors(t *testing.T) {
+	var cli aggregateCLI
+	p := mustNew(t, &cli, kong.AggregateErrors())
+	_, err := p.Parse([]string{"--level=trace", "--colour", "--plain", "--json", "command"})
+	require.Error(t, err)
+	require.IsType(t, kong.ValidationErrors{}, err.(*kong.ParseError).Cause())
+	require.EqualError(t, err, `6 problems:
+- --level must be one of "debug","info" but got "trace"
+- missing flags: --name=STRING
+- missing flags: --token=STRING
+- expected "<arg>"
+- --colour and --plain can't be used together
+- --colour and --json can't be used together`)
+}
+
+func TestAggregateErrorsSingle(t *testing.T) {
+	var cli aggregateCLI
+	p := mustNew(t, &cli, kong.AggregateErrors())
+	_, err := p.Parse([]string{"--name=foo", "command", "--token=bar"})
+	require.EqualError(t, err, `expected "<arg>"`)
+}
+
+func TestFirstErrorByDefault(t *testing.T) {
+	var cli aggregateCLI
+	p := mustNew(t, &cli)
+	_, err := p.Parse([]string{"--level=trace", "--colour", "--plain", "command"})
+	require.EqualError(t, err, `--level must be one of "debug","info" but got "trace"`)
+}
+
+type requiredForCLI struct {
+	Project string `requiredfor:"deploy"`
+	Region  string
+
+	Deploy struct {
+		Prod struct{} `cmd:""`
+	} `cmd:""`
+	Logs    logsCmd  `cmd:""`
+	Version struct{} `cmd:""`
+}
+
+type logsCmd struct{}
+
+func (l *logsCmd) RequiredFlags() []string { return []string{"project", "region"} }
+
+func TestRequiredFor(t *testing.T) {
+	var cli requiredForCLI
+	p := mustNew(t, &cli)
+	_, err := p.Parse([]string{"deploy", "prod"})
+	require.EqualError(t, err, "missing flags: --project=STRING")
+
+	p = mustNew(t, &cli)
+	_, err = p.Parse([]string{"--project=foo", "deploy", "prod"})
+	require.NoError(t, err)
+
+	p = mustNew(t, &cli)
+	_, err = p.Parse([]string{"logs"})
+	require.EqualError(t, err, "missing flags: --project=STRING, --region=STRING")
+
+	p = mustNew(t, &cli)
+	_, err = p.Parse([]string{"version"})
+	require.NoError(t, err)
+}
+
+func TestRequiredForSummary(t *testing.T) {
+	var cli requiredForCLI
+	p := mustNew(t, &cli)
+	summaries := []string{}
+	for _, node := range p.Model.Leaves(true) {
+		summaries = append(summaries, node.Summary())
+	}
+	require.Equal(t, []string{
+		"deploy prod --project=STRING",
+		"logs --project=STRING --region=STRING",
+		"version",
+	}, summaries)
+}
+
+func TestRequiredForNestedCommand(t *testing.T) {
+	type logs struct{}
+	var cli struct {
+		Token  string `requiredfor:"server logs"`
+		Server struct {
+			Logs logs `cmd:""`
+		} `cmd:""`
+		Client struct {
+			Logs logs `cmd:""`
+		} `cmd:""`
+	}
+	_, err := mustNew(t, &cli).Parse([]string{"server", "logs"})
+	require.EqualError(t, err, "missing flags: --token=STRING")
+
+	_, err = mustNew(t, &cli).Parse([]string{"client", "logs"})
+	require.NoError(t, err)
+}
+
+func TestRequiredForAmbiguousName(t *testing.T) {
+	var cli struct {
+		Token  string `requiredfor:"logs"`
+		Server struct {
+			Logs struct{} `cmd:""`
+		} `cmd:""`
+		Client struct {
+			Logs struct{} `cmd:""`
+		} `cmd:""`
+	}
+	_, err := kong.New(&cli)
+	require.EqualError(t, err, `--token: requiredfor refers to unknown command "logs"`)
+}
+
+func TestRequiredForUnknown(t *testing.T) {
+	var cli struct {
+		Project string   `requiredfor:"deploy"`
+		Logs    struct{} `cmd:""`
+	}
+	_, err := kong.New(&cli)
+	require.EqualError(t, err, `--project: requiredfor refers to unknown command "deploy"`)
+
+	var cli2 struct {
+		Logs logsCmd `cmd:""`
+	}
+	_, err = kong.New(&cli2)
+	require.EqualError(t, err, "logs: RequiredFlags() refers to unknown flag --project")
+}
+
 func TestEnumSequence(t *testing.T) {
 	var cli struct {
 		State []string `enum:"a,b,c" default:"a"`
@@ -866,3 +1157,358 @@ func TestLoneHpyhen(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "-", cli.Flag)
 }
+
+func TestSuggestCommandsAcrossTree(t *testing.T) {
+	var cli struct {
+		Server struct {
+			Logs   struct{} `cmd:""`
+			Status struct{} `cmd:""`
+		} `cmd:""`
+		Client struct {
+			Log    struct{} `cmd:""`
+			Secret struct {
+				Logs struct{} `cmd:""`
+			} `cmd:"" hidden:""`
+		} `cmd:""`
+	}
+	p := mustNew(t, &cli)
+
+	_, err := p.Parse([]string{"logs"})
+	require.EqualError(t, err, `unexpected argument logs, did you mean one of "test server logs", "test client log"?`)
+
+	_, err = p.Parse([]string{"client", "status"})
+	require.EqualError(t, err, `unexpected argument status, did you mean "test server status"?`)
+
+	// Siblings take precedence.
+	_, err = p.Parse([]string{"client", "logs"})
+	require.EqualError(t, err, `unexpected argument logs, did you mean "log"?`)
+
+	_, err = p.Parse([]string{"unknown"})
+	require.EqualError(t, err, `unexpected argument unknown`)
+}
+
+func TestEnvArgs(t *testing.T) {
+	var cli struct {
+		Verbose bool
+		Region  string
+		Name    string
+	}
+	restoreEnv := tempEnv(envMap{"KONG_TEST_OPTS": `--verbose --region=eu --name "default name"`})
+	defer restoreEnv()
+	p := mustNew(t, &cli, kong.EnvArgs("KONG_TEST_OPTS"))
+	_, err := p.Parse([]string{"--region=us"})
+	require.NoError(t, err)
+	require.True(t, cli.Verbose)
+	require.Equal(t, "us", cli.Region)
+	require.Equal(t, "default name", cli.Name)
+}
+
+func TestEnvArgsDefaultName(t *testing.T) {
+	var cli struct {
+		Verbose bool
+	}
+	restoreEnv := tempEnv(envMap{"MY_APP_OPTS": "--verbose"})
+	defer restoreEnv()
+	p := mustNew(t, &cli, kong.Name("my-app"), kong.EnvArgs(""))
+	_, err := p.Parse(nil)
+	require.NoError(t, err)
+	require.True(t, cli.Verbose)
+}
+
+func TestEnvArgsCommandFlags(t *testing.T) {
+	var cli struct {
+		Deploy struct {
+			Region string
+		} `cmd:""`
+	}
+	restoreEnv := tempEnv(envMap{"KONG_TEST_OPTS": "--region=eu"})
+	defer restoreEnv()
+	_, err := mustNew(t, &cli, kong.EnvArgs("KONG_TEST_OPTS")).Parse([]string{"deploy"})
+	require.NoError(t, err)
+	require.Equal(t, "eu", cli.Deploy.Region)
+
+	_, err = mustNew(t, &cli, kong.EnvArgs("KONG_TEST_OPTS")).Parse([]string{"deploy", "--region=us"})
+	require.NoError(t, err)
+	require.Equal(t, "us", cli.Deploy.Region)
+
+	restoreEnv = tempEnv(envMap{"KONG_TEST_OPTS": "--regoin=eu"})
+	defer restoreEnv()
+	_, err = mustNew(t, &cli, kong.EnvArgs("KONG_TEST_OPTS")).Parse([]string{"deploy"})
+	require.EqualError(t, err, `$KONG_TEST_OPTS: unknown flag --regoin, did you mean "--region"?`)
+}
+
+func TestEnvArgsErrors(t *testing.T) {
+	var cli struct {
+		Verbose bool
+		Port    int
+	}
+	tests := []struct {
+		env  string
+		args []string
+		err  string
+	}{
+		{env: "--verbos", err: `$KONG_TEST_OPTS: unknown flag --verbos, did you mean "--verbose"?`},
+		{env: "--port 'unterminated", err: "$KONG_TEST_OPTS: unterminated single quote"},
+		{env: "--verbose", args: []string{"--port=http"}, err: `--port: expected a valid 64 bit int but got "http"`},
+		{env: "--verbose --", args: []string{"--port=80"}, err: `$KONG_TEST_OPTS: "--" is not allowed`},
+		{env: "--port", args: []string{"--verbose"}, err: `$KONG_TEST_OPTS: --port: expected int value but got "--verbose" (long flag); perhaps try --port="--verbose"?`},
+	}
+	for _, test := range tests {
+		// nolint: scopelint
+		t.Run(test.env, func(t *testing.T) {
+			restoreEnv := tempEnv(envMap{"KONG_TEST_OPTS": test.env})
+			defer restoreEnv()
+			_, err := mustNew(t, &cli, kong.EnvArgs("KONG_TEST_OPTS")).Parse(test.args)
+			require.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestRestrictedSources(t *testing.T) {
+	var cli struct {
+		Token  string `env:"KONG_TEST_TOKEN" nocli:""`
+		Region string `env:"KONG_TEST_REGION" noconfig:""`
+		Name   string `env:"KONG_TEST_NAME" noenv:""`
+	}
+	restoreEnv := tempEnv(envMap{"KONG_TEST_TOKEN": "secret", "KONG_TEST_REGION": "eu", "KONG_TEST_NAME": "env"})
+	defer restoreEnv()
+
+	_, err := mustNew(t, &cli).Parse(nil)
+	require.NoError(t, err)
+	require.Equal(t, "secret", cli.Token)
+	require.Equal(t, "eu", cli.Region)
+	require.Equal(t, "", cli.Name)
+
+	_, err = mustNew(t, &cli).Parse([]string{"--token=secret"})
+	require.EqualError(t, err, "--token can't be set on the command-line, use $KONG_TEST_TOKEN or configuration instead")
+
+	r, err := kong.JSON(strings.NewReader(`{"region": "us"}`))
+	require.NoError(t, err)
+	_, err = mustNew(t, &cli, kong.Resolvers(r)).Parse(nil)
+	require.NoError(t, err)
+	require.Equal(t, "eu", cli.Region, "noconfig values in configuration are ignored")
+
+	r, err = kong.JSON(strings.NewReader(`{"token": "from-config"}`))
+	require.NoError(t, err)
+	_, err = mustNew(t, &cli, kong.Resolvers(r)).Parse(nil)
+	require.NoError(t, err)
+	require.Equal(t, "from-config", cli.Token)
+}
+
+func TestRestrictedSourcesFromEnvArgs(t *testing.T) {
+	var cli struct {
+		Token string `nocli:""`
+		Name  string `noenv:""`
+	}
+	restoreEnv := tempEnv(envMap{"KONG_TEST_OPTS": "--token=secret"})
+	defer restoreEnv()
+	_, err := mustNew(t, &cli, kong.EnvArgs("KONG_TEST_OPTS")).Parse([]string{"--name=cli"})
+	require.NoError(t, err)
+	require.Equal(t, "secret", cli.Token)
+	require.Equal(t, "cli", cli.Name)
+
+	_, err = mustNew(t, &cli, kong.EnvArgs("KONG_TEST_OPTS")).Parse([]string{"--token=cli"})
+	require.EqualError(t, err, "--token can't be set on the command-line, use $KONG_TEST_OPTS or configuration instead")
+
+	restoreEnv = tempEnv(envMap{"KONG_TEST_OPTS": "--name=env"})
+	defer restoreEnv()
+	_, err = mustNew(t, &cli, kong.EnvArgs("KONG_TEST_OPTS")).Parse(nil)
+	require.EqualError(t, err, "$KONG_TEST_OPTS: --name can't be set from the environment, use the command-line or configuration instead")
+}
+
+func TestRestrictedSourcesUsage(t *testing.T) {
+	var cli struct {
+		Token string `env:"TOKEN" nocli:"" required:""`
+		Name  string `required:""`
+	}
+	p := mustNew(t, &cli)
+	require.Equal(t, "--name=STRING", p.Model.FlagSummary(true))
+}
+
+func TestRestrictedSourcesOnPositional(t *testing.T) {
+	var cli struct {
+		Arg string `arg:"" nocli:""`
+	}
+	_, err := kong.New(&cli)
+	require.Error(t, err)
+}
+
+func TestArity(t *testing.T) {
+	type rename struct {
+		Old string
+		New string
+	}
+	var cli struct {
+		Point  [2]int    `arity:"2" short:"p"`
+		Rename rename    `arity:"2"`
+		Scale  []float64 `arity:"3" default:"1,1,1"`
+		Arg    string    `arg:"" optional:""`
+	}
+	p := mustNew(t, &cli)
+	_, err := p.Parse([]string{"--point", "1", "2", "--rename", "a", "b", "arg"})
+	require.NoError(t, err)
+	require.Equal(t, [2]int{1, 2}, cli.Point)
+	require.Equal(t, rename{"a", "b"}, cli.Rename)
+	require.Equal(t, []float64{1, 1, 1}, cli.Scale)
+	require.Equal(t, "arg", cli.Arg)
+
+	_, err = p.Parse([]string{"--point=3,4", "--scale", "1", "2", "3", "--scale", "4", "5", "6"})
+	require.NoError(t, err)
+	require.Equal(t, [2]int{3, 4}, cli.Point)
+	require.Equal(t, []float64{4, 5, 6}, cli.Scale)
+
+	_, err = p.Parse([]string{"--point", "1", "2", "--point", "3", "4", "--rename=a,b", "--rename", "c", "d"})
+	require.NoError(t, err)
+	require.Equal(t, [2]int{3, 4}, cli.Point)
+	require.Equal(t, rename{"c", "d"}, cli.Rename)
+
+	_, err = p.Parse([]string{"-p5,6"})
+	require.NoError(t, err)
+	require.Equal(t, [2]int{5, 6}, cli.Point)
+
+	// Separate values are not split.
+	_, err = p.Parse([]string{"--rename", "a,b", "new"})
+	require.NoError(t, err)
+	require.Equal(t, rename{"a,b", "new"}, cli.Rename)
+	require.Equal(t, "", cli.Arg)
+
+	_, err = p.Parse([]string{"--point", "1"})
+	require.EqualError(t, err, "--point: expected 2 values but got 1")
+
+	_, err = p.Parse([]string{"--point", "1", "x"})
+	require.Error(t, err)
+}
+
+func TestArityFromResolver(t *testing.T) {
+	var cli struct {
+		Point [2]int `arity:"2"`
+	}
+	r, err := kong.JSON(strings.NewReader(`{"point": [5, 6]}`))
+	require.NoError(t, err)
+	_, err = mustNew(t, &cli, kong.Resolvers(r)).Parse(nil)
+	require.NoError(t, err)
+	require.Equal(t, [2]int{5, 6}, cli.Point)
+}
+
+func TestInvalidArity(t *testing.T) {
+	tests := []interface{}{
+		&struct {
+			Point [3]int `arity:"2"`
+		}{},
+		&struct {
+			Point int `arity:"2"`
+		}{},
+		&struct {
+			Point []int `arity:"0"`
+		}{},
+		&struct {
+			Point []int `arg:"" arity:"2"`
+		}{},
+	}
+	for _, test := range tests {
+		_, err := kong.New(test)
+		require.Error(t, err)
+	}
+}
+
+func TestPositionalOrFlag(t *testing.T) {
+	type cli struct {
+		Get struct {
+			Name string `arg:"" flag:"" help:"Name to get."`
+		} `cmd:""`
+	}
+	tests := []struct {
+		args []string
+		name string
+		err  string
+	}{
+		{args: []string{"get", "foo"}, name: "foo"},
+		{args: []string{"get", "--name", "foo"}, name: "foo"},
+		{args: []string{"get", "--name=foo"}, name: "foo"},
+		{args: []string{"get", "foo", "--name=bar"}, err: "<name> and --name can't both be given"},
+		{args: []string{"get"}, err: `expected "<name>"`},
+	}
+	for _, test := range tests {
+		// nolint: scopelint
+		t.Run(strings.Join(test.args, " "), func(t *testing.T) {
+			var cli cli
+			_, err := mustNew(t, &cli).Parse(test.args)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.name, cli.Get.Name)
+		})
+	}
+}
+
+func TestPositionalOrFlagRequiresArg(t *testing.T) {
+	var cli struct {
+		Name string `flag:""`
+	}
+	_, err := kong.New(&cli)
+	require.Error(t, err)
+}
+
+func TestPreprocessors(t *testing.T) {
+	var cli struct {
+		Jobs  int
+		Debug bool
+		Args  []string `arg:"" optional:""`
+	}
+	jobs := func(tokens []kong.Token) ([]kong.Token, error) {
+		out := make([]kong.Token, 0, len(tokens))
+		for _, token := range tokens {
+			if s, ok := token.Value.(string); ok && strings.HasPrefix(s, "-j") && len(s) > 2 {
+				token.Value = "--jobs=" + s[2:]
+			}
+			out = append(out, token)
+		}
+		return out, nil
+	}
+	plus := func(tokens []kong.Token) ([]kong.Token, error) {
+		out := make([]kong.Token, 0, len(tokens))
+		for _, token := range tokens {
+			if s, ok := token.Value.(string); ok && strings.HasPrefix(s, "+") {
+				token.Value = "--" + s[1:]
+			}
+			out = append(out, token)
+		}
+		return out, nil
+	}
+	// Rewrites "+jobs4" to "-j4", which relies on being applied before the other preprocessors.
+	legacy := func(tokens []kong.Token) ([]kong.Token, error) {
+		for i, token := range tokens {
+			if s, ok := token.Value.(string); ok && strings.HasPrefix(s, "+jobs") {
+				tokens[i].Value = "-j" + s[5:]
+			}
+		}
+		return tokens, nil
+	}
+	p := mustNew(t, &cli, kong.Preprocessors(legacy, jobs, plus))
+	_, err := p.Parse([]string{"-j4", "+debug", "arg"})
+	require.NoError(t, err)
+	require.Equal(t, 4, cli.Jobs)
+	require.True(t, cli.Debug)
+	require.Equal(t, []string{"arg"}, cli.Args)
+
+	_, err = p.Parse([]string{"+jobs8"})
+	require.NoError(t, err)
+	require.Equal(t, 8, cli.Jobs)
+
+	_, err = p.Parse([]string{"arg", "-jx"})
+	require.EqualError(t, err, `--jobs: expected a valid 64 bit int but got "x" (in argument 2 "-jx")`)
+
+	_, err = p.Parse([]string{"--jobs=x"})
+	require.EqualError(t, err, `--jobs: expected a valid 64 bit int but got "x"`)
+}
+
+func TestPreprocessorError(t *testing.T) {
+	var cli struct{}
+	failing := func(tokens []kong.Token) ([]kong.Token, error) {
+		return nil, fmt.Errorf("invalid syntax")
+	}
+	_, err := mustNew(t, &cli, kong.Preprocessors(failing)).Parse(nil)
+	require.EqualError(t, err, "invalid syntax")
+}
@@ -0,0 +1,126 @@
+package kong
+
+import (
+	"fmt"
+	"os"
+)
+
+// ValueSource identifies where a value was loaded from.
+type ValueSource int
+
+// Value sources.
+const (
+	SourceNone     ValueSource = iota // Not set, so has its zero value.
+	SourceDefault                     // From the "default" tag.
+	SourceEnv                         // From the environment variable in the "env" tag.
+	SourceResolver                    // From a Resolver, such as a configuration file.
+)
+
+func (v ValueSource) String() string {
+	switch v {
+	case SourceNone:
+		return "none"
+	case SourceDefault:
+		return "default"
+	case SourceEnv:
+		return "environment"
+	case SourceResolver:
+		return "resolver"
+	}
+	return fmt.Sprintf("ValueSource(%d)", int(v))
+}
+
+// Provenance records where each value loaded by Load() came from, keyed by flag name.
+type Provenance map[string]ValueSource
+
+// LoadError is the error type returned by Load().
+//
+// It contains the Flag, if any, and source of the value that failed to load, and the provenance of the values loaded
+// before the failure.
+type LoadError struct {
+	error
+	Flag       *Flag
+	Source     ValueSource
+	Provenance Provenance
+}
+
+// Cause returns the original cause of the error.
+func (l *LoadError) Cause() error { return l.error }
+
+// Unwrap returns the original cause of the error.
+func (l *LoadError) Unwrap() error { return l.error }
+
+// Load a configuration struct from environment variables, resolvers and defaults, without a command-line.
+//
+// "target" must be a pointer to a struct using Kong tags, but may not contain commands or positional arguments.
+// Values are loaded with the same precedence as Parse(): resolvers (eg. configuration files added with
+// Configuration()), then environment variables, then defaults. Loaded values are then validated.
+//
+// Hooks are not called, and there is no help flag. All errors are of type *LoadError.
+func Load(target interface{}, options ...Option) (Provenance, error) { // nolint: gocyclo
+	provenance := Provenance{}
+	loadError := func(err error, flag *Flag, source ValueSource) error {
+		return &LoadError{error: err, Flag: flag, Source: source, Provenance: provenance}
+	}
+	k, err := New(target, append(options, NoDefaultHelp())...)
+	if err != nil {
+		return nil, loadError(err, nil, SourceNone)
+	}
+	if len(k.Model.Children) > 0 || len(k.Model.Positional) > 0 {
+		return nil, loadError(fmt.Errorf("%T must not contain commands or positional arguments", target), nil, SourceNone)
+	}
+	ctx, err := Trace(k, nil)
+	if err != nil {
+		return nil, loadError(err, nil, SourceNone)
+	}
+	if ctx.Error != nil {
+		return nil, loadError(ctx.Error, nil, SourceNone)
+	}
+
+	for _, flag := range k.Model.Flags {
+		source := SourceNone
+		switch {
+		case flag.Tag.Env != "" && !flag.Tag.NoEnv && os.Getenv(flag.Tag.Env) != "":
+			source = SourceEnv
+		case flag.Default != "":
+			source = SourceDefault
+		}
+		if err := flag.Reset(); err != nil {
+			return nil, loadError(err, flag, source)
+		}
+		provenance[flag.Name] = source
+	}
+
+	resolvers := ctx.combineResolvers()
+	for _, flag := range k.Model.Flags {
+		resolved, err := ctx.resolveFlag(resolvers, ctx.Path[0], flag)
+		if err != nil {
+			return nil, loadError(err, flag, SourceResolver)
+		}
+		if len(resolved) > 0 {
+			provenance[flag.Name] = SourceResolver
+			ctx.Path = append(ctx.Path, resolved...)
+		}
+	}
+	if _, err := ctx.Apply(); err != nil {
+		return nil, loadError(err, nil, SourceNone)
+	}
+
+	for _, resolver := range resolvers {
+		if err := resolver.Validate(k.Model); err != nil {
+			return nil, loadError(err, nil, SourceResolver)
+		}
+	}
+	secrets := ctx.secretValues()
+	for _, flag := range k.Model.Flags {
+		if flag.Required && !flag.Set {
+			return nil, loadError(fmt.Errorf("%s is required", flag.ShortSummary()), flag, SourceNone)
+		}
+		if flag.Enum != "" && (flag.Set || !flag.Required) {
+			if err := checkEnum(flag.Value, flag.Target, secrets[flag.Value]); err != nil {
+				return nil, loadError(err, flag, provenance[flag.Name])
+			}
+		}
+	}
+	return provenance, nil
+}
@@ -0,0 +1,127 @@
+package kong_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/alecthomas/kong"
+)
+
+type loadConfig struct {
+	Name    string        `env:"KONG_LOAD_NAME" default:"service"`
+	Port    int           `default:"8080"`
+	Timeout time.Duration `default:"30s"`
+	Mode    string        `enum:"fast,safe" default:"safe"`
+	Token   string
+	DB      struct {
+		Host string `default:"localhost"`
+	} `embed:"" prefix:"db-"`
+}
+
+func TestLoad(t *testing.T) {
+	restoreEnv := tempEnv(envMap{"KONG_LOAD_NAME": "api"})
+	defer restoreEnv()
+
+	r, err := kong.JSON(strings.NewReader(`{"port": 9090, "db_host": "db.example.com"}`))
+	require.NoError(t, err)
+
+	var cfg loadConfig
+	provenance, err := kong.Load(&cfg, kong.Resolvers(r))
+	require.NoError(t, err)
+	require.Equal(t, "api", cfg.Name)
+	require.Equal(t, 9090, cfg.Port)
+	require.Equal(t, time.Second*30, cfg.Timeout)
+	require.Equal(t, "safe", cfg.Mode)
+	require.Equal(t, "db.example.com", cfg.DB.Host)
+	require.Equal(t, kong.Provenance{
+		"name":    kong.SourceEnv,
+		"port":    kong.SourceResolver,
+		"timeout": kong.SourceDefault,
+		"mode":    kong.SourceDefault,
+		"token":   kong.SourceNone,
+		"db-host": kong.SourceResolver,
+	}, provenance)
+}
+
+func TestLoadErrors(t *testing.T) {
+	t.Run("Env", func(t *testing.T) {
+		var cfg struct {
+			Port int `env:"KONG_LOAD_PORT"`
+		}
+		restoreEnv := tempEnv(envMap{"KONG_LOAD_PORT": "http"})
+		defer restoreEnv()
+		_, err := kong.Load(&cfg)
+		require.Error(t, err)
+		loadErr, ok := err.(*kong.LoadError)
+		require.True(t, ok)
+		require.Equal(t, "port", loadErr.Flag.Name)
+		require.Equal(t, kong.SourceEnv, loadErr.Source)
+	})
+
+	t.Run("Resolver", func(t *testing.T) {
+		var cfg struct {
+			Mode string `enum:"fast,safe" default:"safe"`
+		}
+		r, err := kong.JSON(strings.NewReader(`{"mode": "slow"}`))
+		require.NoError(t, err)
+		_, err = kong.Load(&cfg, kong.Resolvers(r))
+		require.EqualError(t, err, `--mode must be one of "fast","safe" but got "slow"`)
+		require.Equal(t, kong.SourceResolver, err.(*kong.LoadError).Source)
+	})
+
+	t.Run("Required", func(t *testing.T) {
+		var cfg struct {
+			Token string `required:""`
+		}
+		_, err := kong.Load(&cfg)
+		require.EqualError(t, err, "--token is required")
+		require.Equal(t, "token", err.(*kong.LoadError).Flag.Name)
+	})
+
+	t.Run("Commands", func(t *testing.T) {
+		var cfg struct {
+			Cmd struct{} `cmd:""`
+		}
+		_, err := kong.Load(&cfg)
+		var loadErr *kong.LoadError
+		require.True(t, errors.As(err, &loadErr))
+		require.Nil(t, loadErr.Flag)
+		require.Equal(t, kong.SourceNone, loadErr.Source)
+		require.Contains(t, err.Error(), "must not contain commands or positional arguments")
+	})
+
+	t.Run("Positionals", func(t *testing.T) {
+		var cfg struct {
+			Arg string `arg:""`
+		}
+		_, err := kong.Load(&cfg)
+		require.IsType(t, &kong.LoadError{}, err)
+	})
+
+	t.Run("Build", func(t *testing.T) {
+		var cfg struct {
+			Flag string `shortonly:""`
+		}
+		_, err := kong.Load(&cfg)
+		require.EqualError(t, err, "shortonly requires a short flag")
+		require.IsType(t, &kong.LoadError{}, err)
+	})
+
+	t.Run("Provenance", func(t *testing.T) {
+		var cfg struct {
+			Name string `default:"service"`
+			Port int    `env:"KONG_LOAD_PORT"`
+		}
+		restoreEnv := tempEnv(envMap{"KONG_LOAD_PORT": "http"})
+		defer restoreEnv()
+		_, err := kong.Load(&cfg)
+		require.Error(t, err)
+		loadErr, ok := err.(*kong.LoadError)
+		require.True(t, ok)
+		require.Equal(t, kong.Provenance{"name": kong.SourceDefault}, loadErr.Provenance)
+	})
+}
@@ -243,6 +243,8 @@ func (r *Registry) RegisterDefaults() *Registry {
 			return err
 		})).
 		RegisterKind(reflect.Bool, boolMapper{}).
+		RegisterType(reflect.TypeOf((*bool)(nil)), boolMapper{}).
+		RegisterType(reflect.TypeOf(TriBool(0)), boolMapper{}).
 		RegisterKind(reflect.Slice, sliceDecoder(r)).
 		RegisterKind(reflect.Map, mapDecoder(r)).
 		RegisterType(reflect.TypeOf(time.Time{}), timeDecoder()).
@@ -254,38 +256,149 @@ func (r *Registry) RegisterDefaults() *Registry {
 		RegisterName("counter", counterMapper())
 }
 
-type boolMapper struct{}
+// A BoolVocabulary is a pair of words accepted for true and false boolean values, eg. on/off.
+type BoolVocabulary struct {
+	True, False string
+}
+
+// DefaultBoolVocabularies are the words accepted for boolean values unless overridden by the BoolValues option or the
+// "boolvalues" tag.
+var DefaultBoolVocabularies = []BoolVocabulary{{"true", "false"}, {"1", "0"}, {"yes", "no"}}
 
-func (boolMapper) Decode(ctx *DecodeContext, target reflect.Value) error {
+// TriBool is a boolean flag value that can also be set to "auto", eg. --colour=auto.
+//
+// Unlike a *bool, which is nil until set, a TriBool distinguishes an unset flag from one explicitly set to auto.
+type TriBool int
+
+// Values of a TriBool.
+const (
+	TriBoolUnset TriBool = iota
+	TriBoolFalse
+	TriBoolTrue
+	TriBoolAuto
+)
+
+// Word accepted by TriBool values for TriBoolAuto.
+const autoBoolValue = "auto"
+
+func (t TriBool) String() string {
+	switch t {
+	case TriBoolFalse:
+		return "false"
+	case TriBoolTrue:
+		return "true"
+	case TriBoolAuto:
+		return autoBoolValue
+	default:
+		return ""
+	}
+}
+
+type boolMapper struct {
+	vocabularies []BoolVocabulary
+}
+
+// Vocabularies accepted by value, from its "boolvalues" tag or the mapper.
+func (b boolMapper) vocabulariesFor(value *Value) []BoolVocabulary {
+	if value != nil && len(value.Tag.BoolValues) > 0 {
+		return value.Tag.BoolValues
+	}
+	if b.vocabularies != nil {
+		return b.vocabularies
+	}
+	return DefaultBoolVocabularies
+}
+
+func (b boolMapper) Decode(ctx *DecodeContext, target reflect.Value) error {
+	optional := target.Kind() == reflect.Ptr
+	triBool := target.Type() == reflect.TypeOf(TriBool(0))
+	value := true
 	if ctx.Scan.Peek().Type == FlagValueToken {
 		token := ctx.Scan.Pop()
 		switch v := token.Value.(type) {
 		case string:
 			v = strings.ToLower(v)
-			switch v {
-			case "true", "1", "yes":
-				target.SetBool(true)
-
-			case "false", "0", "no":
-				target.SetBool(false)
-
-			default:
-				return errors.Errorf("bool value must be true, 1, yes, false, 0 or no but got %q", v)
+			if triBool && v == autoBoolValue {
+				target.Set(reflect.ValueOf(TriBoolAuto))
+				return nil
+			}
+			vocabularies := b.vocabulariesFor(ctx.Value)
+			found := false
+			for _, vocabulary := range vocabularies {
+				if v == strings.ToLower(vocabulary.True) || v == strings.ToLower(vocabulary.False) {
+					value = v == strings.ToLower(vocabulary.True)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return errors.Errorf("bool value must be %s but got %q", describeBoolValues(vocabularies, triBool), v)
 			}
 
 		case bool:
-			target.SetBool(v)
+			value = v
+
+		case nil:
+			if !optional && !triBool {
+				return errors.Errorf("expected bool but got %q (%T)", token.Value, token.Value)
+			}
+			target.Set(reflect.Zero(target.Type()))
+			return nil
 
 		default:
 			return errors.Errorf("expected bool but got %q (%T)", token.Value, token.Value)
 		}
-	} else {
-		target.SetBool(true)
+	}
+	switch {
+	case optional:
+		target.Set(reflect.ValueOf(&value))
+	case triBool && value:
+		target.Set(reflect.ValueOf(TriBoolTrue))
+	case triBool:
+		target.Set(reflect.ValueOf(TriBoolFalse))
+	default:
+		target.SetBool(value)
 	}
 	return nil
 }
 func (boolMapper) IsBool() bool { return true }
 
+// eg. "true, 1, yes, false, 0 or no"
+func describeBoolValues(vocabularies []BoolVocabulary, auto bool) string {
+	words := []string{}
+	for _, vocabulary := range vocabularies {
+		words = append(words, vocabulary.True)
+	}
+	for _, vocabulary := range vocabularies {
+		words = append(words, vocabulary.False)
+	}
+	if auto {
+		words = append(words, autoBoolValue)
+	}
+	if len(words) == 1 {
+		return words[0]
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " or " + words[len(words)-1]
+}
+
+// Hint for the values accepted by a boolean flag in help, eg. "[=on|off]", or "" if the defaults are accepted.
+func boolValuesHint(flag *Flag) string {
+	mapper, ok := flag.Mapper.(boolMapper)
+	if !ok {
+		return ""
+	}
+	triBool := flag.Target.Type() == reflect.TypeOf(TriBool(0))
+	if len(flag.Tag.BoolValues) == 0 && mapper.vocabularies == nil && !triBool {
+		return ""
+	}
+	vocabulary := mapper.vocabulariesFor(flag.Value)[0]
+	words := []string{vocabulary.True, vocabulary.False}
+	if triBool {
+		words = append(words, autoBoolValue)
+	}
+	return "[=" + strings.Join(words, "|") + "]"
+}
+
 func durationDecoder() MapperFunc {
 	return func(ctx *DecodeContext, target reflect.Value) error {
 		var value string
@@ -418,7 +531,15 @@ func mapDecoder(r *Registry) MapperFunc {
 			}
 			switch v := t.Value.(type) {
 			case string:
-				childScanner = Scan(SplitEscaped(v, sep)...)
+				if ctx.Value.Tag.Quoted {
+					entries, err := splitQuoted(v, sep, -1, true)
+					if err != nil {
+						return err
+					}
+					childScanner = Scan(entries...)
+				} else {
+					childScanner = Scan(SplitEscaped(v, sep)...)
+				}
 
 			case []map[string]interface{}:
 				for _, m := range v {
@@ -445,7 +566,14 @@ func mapDecoder(r *Registry) MapperFunc {
 			if err != nil {
 				return err
 			}
-			parts := strings.SplitN(token, "=", 2)
+			var parts []string
+			if ctx.Value.Tag.Quoted {
+				if parts, err = splitQuoted(token, '=', 2, false); err != nil {
+					return err
+				}
+			} else {
+				parts = strings.SplitN(token, "=", 2)
+			}
 			if len(parts) != 2 {
 				return errors.Errorf("expected \"<key>=<value>\" but got %q", token)
 			}
@@ -493,7 +621,11 @@ func sliceDecoder(r *Registry) MapperFunc {
 			}
 			switch v := t.Value.(type) {
 			case string:
-				childScanner = Scan(SplitEscaped(v, sep)...)
+				values, err := splitValues(ctx.Value, v, sep)
+				if err != nil {
+					return err
+				}
+				childScanner = Scan(values...)
 
 			case []interface{}:
 				return jsonTranscode(v, target.Addr().Interface())
@@ -522,6 +654,80 @@ func sliceDecoder(r *Registry) MapperFunc {
 	}
 }
 
+// Decode a flag with an "arity" tag, consuming that many value tokens into an array, slice or struct.
+//
+// A single value attached to the flag, such as from "--flag=a,b", an environment variable or a default, is instead
+// split on the separator. Repeating the flag replaces the previous values.
+func arityDecoder(r *Registry) MapperFunc {
+	return func(ctx *DecodeContext, target reflect.Value) error {
+		arity := ctx.Value.Tag.Arity
+		t, err := ctx.Scan.PopValue("value")
+		if err != nil {
+			return err
+		}
+		var values []string
+		switch v := t.Value.(type) {
+		case string:
+			if t.Type.IsAny(FlagValueToken, ShortFlagTailToken) {
+				if values, err = splitValues(ctx.Value, v, ctx.Value.Tag.Sep); err != nil {
+					return err
+				}
+			} else {
+				values = []string{v}
+				for len(values) < arity {
+					t, err := ctx.Scan.PopValue("value")
+					if err != nil {
+						return errors.Errorf("expected %d values but got %d", arity, len(values))
+					}
+					values = append(values, t.String())
+				}
+			}
+			if len(values) != arity {
+				return errors.Errorf("expected %d values but got %d", arity, len(values))
+			}
+
+		default:
+			// Eg. a list or object from a configuration file.
+			return jsonTranscode(v, target.Addr().Interface())
+		}
+
+		tokens := make([]Token, len(values))
+		for i, value := range values {
+			tokens[i] = Token{Type: FlagValueToken, Value: value}
+		}
+		childCtx := ctx.WithScanner(ScanFromTokens(tokens...))
+		switch target.Kind() {
+		case reflect.Struct:
+			for i := 0; i < target.NumField(); i++ {
+				field := target.Field(i)
+				decoder := r.ForValue(field)
+				if decoder == nil {
+					return errors.Errorf("no mapper for %s", field.Type())
+				}
+				if err := decoder.Decode(childCtx, field); err != nil {
+					return err
+				}
+			}
+
+		case reflect.Array, reflect.Slice:
+			el := target.Type().Elem()
+			decoder := r.ForNamedType(ctx.Value.Tag.Type, el)
+			if decoder == nil {
+				return errors.Errorf("no mapper for element type of %s", target.Type())
+			}
+			if target.Kind() == reflect.Slice {
+				target.Set(reflect.MakeSlice(target.Type(), arity, arity))
+			}
+			for i := 0; i < arity; i++ {
+				if err := decoder.Decode(childCtx, target.Index(i)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+}
+
 func pathMapper(r *Registry) MapperFunc {
 	return func(ctx *DecodeContext, target reflect.Value) error {
 		if target.Kind() == reflect.Slice {
@@ -630,6 +836,14 @@ func urlMapper() MapperFunc {
 	}
 }
 
+// Split a flag value on sep, using quoting if the value is tagged "quoted" or escaping otherwise.
+func splitValues(value *Value, s string, sep rune) ([]string, error) {
+	if value.Tag.Quoted {
+		return SplitQuoted(s, sep)
+	}
+	return SplitEscaped(s, sep), nil
+}
+
 // SplitEscaped splits a string on a separator.
 //
 // It differs from strings.Split() in that the separator can exist in a field by escaping it with a \. eg.
@@ -659,6 +873,70 @@ func SplitEscaped(s string, sep rune) (out []string) {
 	return
 }
 
+// SplitQuoted splits a string on a separator, with CSV-style quoting.
+//
+// Separators within double quotes are not split on, and a doubled quote within quotes is a literal quote. eg.
+//
+//     SplitQuoted(`"hello,there",bob,"say ""hi"""`, ',') == []string{"hello,there", "bob", `say "hi"`}
+func SplitQuoted(s string, sep rune) ([]string, error) {
+	return splitQuoted(s, sep, -1, false)
+}
+
+// Split s on sep into at most n fields (or all fields if n < 0), respecting double quotes.
+//
+// If keepQuotes is true, quotes are retained in the fields so that they can be split again.
+func splitQuoted(s string, sep rune, n int, keepQuotes bool) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	out := []string{}
+	field := strings.Builder{}
+	quoted := false
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		ch := runes[i]
+		switch {
+		case ch == '"' && quoted && i+1 < len(runes) && runes[i+1] == '"':
+			if keepQuotes {
+				field.WriteRune(ch)
+			}
+			field.WriteRune(ch)
+			i++
+		case ch == '"':
+			quoted = !quoted
+			if keepQuotes {
+				field.WriteRune(ch)
+			}
+		case ch == sep && !quoted && (n < 0 || len(out) < n-1):
+			out = append(out, field.String())
+			field.Reset()
+		default:
+			field.WriteRune(ch)
+		}
+	}
+	if quoted {
+		return nil, errors.Errorf("unterminated quote in %q", s)
+	}
+	return append(out, field.String()), nil
+}
+
+// JoinQuoted joins a slice of strings on sep, quoting fields containing sep or quotes such that SplitQuoted() will
+// return the original fields. eg.
+//
+//     JoinQuoted([]string{"hello,there", "bob"}, ',') == `"hello,there",bob`
+//
+// A single empty field is quoted, as an empty string splits into no fields.
+func JoinQuoted(s []string, sep rune) string {
+	quoted := make([]string, len(s))
+	for i, e := range s {
+		if strings.ContainsRune(e, sep) || strings.ContainsRune(e, '"') || (e == "" && len(s) == 1) {
+			e = `"` + strings.Replace(e, `"`, `""`, -1) + `"`
+		}
+		quoted[i] = e
+	}
+	return strings.Join(quoted, string(sep))
+}
+
 // JoinEscaped joins a slice of strings on sep, but also escapes any instances of sep in the fields with \. eg.
 //
 //     JoinEscaped([]string{"hello,there", "bob"}, ',') == `hello\,there,bob`
@@ -138,6 +138,171 @@ func TestJoinEscaped(t *testing.T) {
 	require.Equal(t, kong.JoinEscaped(kong.SplitEscaped(`a\,b,c`, ','), ','), `a\,b,c`)
 }
 
+func TestSplitQuoted(t *testing.T) {
+	actual, err := kong.SplitQuoted(`"a,b",c`, ',')
+	require.NoError(t, err)
+	require.Equal(t, []string{"a,b", "c"}, actual)
+	actual, err = kong.SplitQuoted(`"say ""hi""",,x"y,z"`, ',')
+	require.NoError(t, err)
+	require.Equal(t, []string{`say "hi"`, "", "xy,z"}, actual)
+	_, err = kong.SplitQuoted(`"a,b`, ',')
+	require.EqualError(t, err, `unterminated quote in "\"a,b"`)
+}
+
+func TestJoinQuoted(t *testing.T) {
+	require.Equal(t, `a,b`, kong.JoinQuoted([]string{"a", "b"}, ','))
+	require.Equal(t, `"a,b",c,"say ""hi"""`, kong.JoinQuoted([]string{"a,b", "c", `say "hi"`}, ','))
+	require.Equal(t, `""`, kong.JoinQuoted([]string{""}, ','))
+}
+
+func TestJoinQuotedRoundTrip(t *testing.T) {
+	tests := [][]string{
+		nil,
+		{""},
+		{"", ""},
+		{"a", ""},
+		{"", "a"},
+		{"a,b", `"`, "c"},
+		{`""`},
+		{`say "hi"`, ",", ""},
+		{"a b", " "},
+	}
+	for _, test := range tests {
+		// nolint: scopelint
+		t.Run(fmt.Sprintf("%q", test), func(t *testing.T) {
+			actual, err := kong.SplitQuoted(kong.JoinQuoted(test, ','), ',')
+			require.NoError(t, err)
+			require.Equal(t, test, actual)
+		})
+	}
+}
+
+func TestQuotedSlicesAndMaps(t *testing.T) {
+	var cli struct {
+		Tags []string          `quoted:""`
+		Env  map[string]string `quoted:""`
+		Args map[string]string `arg:"" optional:"" quoted:""`
+	}
+	p := mustNew(t, &cli)
+	_, err := p.Parse([]string{`--tags="a,b",c`, `--env=K="x=y";"A=B"=c;"D;E"=f`, `"k=1"=v`})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a,b", "c"}, cli.Tags)
+	require.Equal(t, map[string]string{"K": "x=y", "A=B": "c", "D;E": "f"}, cli.Env)
+	require.Equal(t, map[string]string{"k=1": "v"}, cli.Args)
+
+	_, err = p.Parse([]string{`--tags="a,b`})
+	require.EqualError(t, err, `--tags: unterminated quote in "\"a,b"`)
+}
+
+func TestBoolErrorMessage(t *testing.T) {
+	var cli struct {
+		Flag bool
+	}
+	_, err := mustNew(t, &cli).Parse([]string{"--flag=maybe"})
+	require.EqualError(t, err, `--flag: bool value must be true, 1, yes, false, 0 or no but got "maybe"`)
+}
+
+func TestOptionalBool(t *testing.T) {
+	type cliType struct {
+		Colour *bool `env:"COLOUR"`
+	}
+	tests := []struct {
+		args     []string
+		expected *bool
+	}{
+		{nil, nil},
+		{[]string{"--colour"}, boolPtr(true)},
+		{[]string{"--colour=false"}, boolPtr(false)},
+		{[]string{"--colour=YES"}, boolPtr(true)},
+	}
+	for _, test := range tests {
+		// nolint: scopelint
+		t.Run(strings.Join(test.args, " "), func(t *testing.T) {
+			var cli cliType
+			_, err := mustNew(t, &cli).Parse(test.args)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, cli.Colour)
+		})
+	}
+
+	var cli cliType
+	_, err := mustNew(t, &cli).Parse([]string{"--colour=auto"})
+	require.EqualError(t, err, `--colour: bool value must be true, 1, yes, false, 0 or no but got "auto"`)
+
+	defer tempEnv(envMap{"COLOUR": "no"})()
+	_, err = mustNew(t, &cli).Parse(nil)
+	require.NoError(t, err)
+	require.Equal(t, boolPtr(false), cli.Colour)
+}
+
+func TestTriBool(t *testing.T) {
+	type cliType struct {
+		Colour kong.TriBool `env:"COLOUR"`
+	}
+	tests := []struct {
+		args     []string
+		expected kong.TriBool
+	}{
+		{nil, kong.TriBoolUnset},
+		{[]string{"--colour"}, kong.TriBoolTrue},
+		{[]string{"--colour=false"}, kong.TriBoolFalse},
+		{[]string{"--colour=YES"}, kong.TriBoolTrue},
+		{[]string{"--colour=auto"}, kong.TriBoolAuto},
+		{[]string{"--colour", "--colour=AUTO"}, kong.TriBoolAuto},
+	}
+	for _, test := range tests {
+		// nolint: scopelint
+		t.Run(strings.Join(test.args, " "), func(t *testing.T) {
+			var cli cliType
+			_, err := mustNew(t, &cli).Parse(test.args)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, cli.Colour)
+		})
+	}
+	require.NotEqual(t, kong.TriBoolUnset, kong.TriBoolAuto)
+
+	var cli cliType
+	_, err := mustNew(t, &cli).Parse([]string{"--colour=sometimes"})
+	require.EqualError(t, err, `--colour: bool value must be true, 1, yes, false, 0, no or auto but got "sometimes"`)
+
+	defer tempEnv(envMap{"COLOUR": "auto"})()
+	_, err = mustNew(t, &cli).Parse(nil)
+	require.NoError(t, err)
+	require.Equal(t, kong.TriBoolAuto, cli.Colour)
+}
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestBoolValuesTag(t *testing.T) {
+	var cli struct {
+		Cache bool `boolvalues:"on/off,enable/disable"`
+		Debug bool
+	}
+	p := mustNew(t, &cli)
+	_, err := p.Parse([]string{"--cache=disable", "--debug=yes"})
+	require.NoError(t, err)
+	require.False(t, cli.Cache)
+	require.True(t, cli.Debug)
+
+	_, err = p.Parse([]string{"--cache=yes"})
+	require.EqualError(t, err, `--cache: bool value must be on, enable, off or disable but got "yes"`)
+}
+
+func TestBoolValuesOption(t *testing.T) {
+	var cli struct {
+		Cache  bool
+		Colour *bool
+	}
+	p := mustNew(t, &cli, kong.BoolValues(kong.BoolVocabulary{True: "y", False: "n"}))
+	_, err := p.Parse([]string{"--cache=Y", "--colour=n"})
+	require.NoError(t, err)
+	require.True(t, cli.Cache)
+	require.Equal(t, boolPtr(false), cli.Colour)
+
+	_, err = p.Parse([]string{"--cache=true"})
+	require.EqualError(t, err, `--cache: bool value must be y or n but got "true"`)
+}
+
 func TestMapWithNamedTypes(t *testing.T) {
 	var cli struct {
 		TypedValue map[string]string `type:":moo"`
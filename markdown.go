@@ -0,0 +1,248 @@
+package kong
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ANSI escape sequences used when rendering Markdown with colour.
+const (
+	ansiReset     = "\x1b[0m"
+	ansiBold      = "\x1b[1m"
+	ansiItalic    = "\x1b[3m"
+	ansiUnderline = "\x1b[4m"
+	ansiCode      = "\x1b[36m"
+)
+
+var (
+	markdownListItem = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	markdownHeading  = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*$`)
+	markdownInline   = regexp.MustCompile("\\*\\*(.+?)\\*\\*|__(.+?)__|\\*(.+?)\\*|\\b_(.+?)_\\b|`([^`]+)`|\\[([^\\]]+)\\]\\(([^)]+)\\)|<(https?://[^>]+)>")
+)
+
+// A run of text with a single style.
+type markdownRun struct {
+	text  string
+	style string
+}
+
+// A word in rendered Markdown, possibly made up of multiple styled runs, eg. "**bold**,".
+type markdownWord []markdownRun
+
+// Render the word, returning the rendered text and its visible length.
+func (w markdownWord) render(colour bool) (string, int) {
+	out := ""
+	length := 0
+	for _, run := range w {
+		length += utf8.RuneCountInString(run.text)
+		if colour && run.style != "" {
+			out += run.style + run.text + ansiReset
+		} else {
+			out += run.text
+		}
+	}
+	return out, length
+}
+
+// Render a subset of Markdown (paragraphs, headings, lists, code blocks, emphasis, code spans and links) to lines
+// of terminal text wrapped to width.
+//
+// If colour is true, ANSI escape sequences are used for styling, otherwise Markdown markup is removed.
+func renderMarkdown(text string, width int, colour bool) []string {
+	out := []string{}
+	block := func() {
+		if len(out) > 0 && out[len(out)-1] != "" {
+			out = append(out, "")
+		}
+	}
+	lines := strings.Split(strings.Trim(text, "\n"), "\n")
+	paragraph := []string{}
+	inList := false
+	flush := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		inList = false
+		block()
+		out = append(out, wrapMarkdown(parseMarkdownInline(strings.Join(paragraph, " ")), width, "", "", colour)...)
+		paragraph = nil
+	}
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+
+		// Fenced code block.
+		case strings.HasPrefix(trimmed, "```"):
+			flush()
+			block()
+			inList = false
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				out = append(out, renderMarkdownCode(lines[i], colour))
+			}
+
+		// Indented code block, but not a paragraph or list continuation.
+		case len(paragraph) == 0 && !inList && (strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")):
+			block()
+			inList = false
+			for ; i < len(lines); i++ {
+				if !strings.HasPrefix(lines[i], "    ") && !strings.HasPrefix(lines[i], "\t") && strings.TrimSpace(lines[i]) != "" {
+					break
+				}
+				code := strings.TrimPrefix(strings.TrimPrefix(lines[i], "\t"), "    ")
+				out = append(out, renderMarkdownCode(code, colour))
+			}
+			i--
+			// Trailing blank lines belong to the following block.
+			for len(out) > 0 && strings.TrimSpace(out[len(out)-1]) == "" {
+				out = out[:len(out)-1]
+			}
+
+		case markdownHeading.MatchString(line):
+			flush()
+			block()
+			inList = false
+			words := parseMarkdownInline(markdownHeading.FindStringSubmatch(line)[1])
+			for _, word := range words {
+				for i := range word {
+					word[i].style = ansiBold + word[i].style
+				}
+			}
+			out = append(out, wrapMarkdown(words, width, "", "", colour)...)
+
+		case markdownListItem.MatchString(line):
+			flush()
+			if !inList {
+				block()
+			}
+			inList = true
+			match := markdownListItem.FindStringSubmatch(line)
+			indent := strings.Repeat(" ", len(match[1])/2*defaultIndent)
+			bullet := match[2]
+			if strings.ContainsAny(bullet, "-*+") {
+				bullet = "-"
+			}
+			item := match[3]
+			// Gather continuation lines.
+			for i+1 < len(lines) {
+				next := lines[i+1]
+				if strings.TrimSpace(next) == "" || markdownListItem.MatchString(next) || !strings.HasPrefix(next, " ") {
+					break
+				}
+				item += " " + strings.TrimSpace(next)
+				i++
+			}
+			first := indent + bullet + " "
+			rest := indent + strings.Repeat(" ", utf8.RuneCountInString(bullet)+1)
+			out = append(out, wrapMarkdown(parseMarkdownInline(item), width, first, rest, colour)...)
+
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	return out
+}
+
+func renderMarkdownCode(line string, colour bool) string {
+	line = strings.TrimRight(line, " \t")
+	if line == "" {
+		return ""
+	}
+	if colour {
+		return "    " + ansiCode + line + ansiReset
+	}
+	return "    " + line
+}
+
+// Parse inline Markdown into styled words.
+func parseMarkdownInline(text string) (words []markdownWord) {
+	joining := false
+	add := func(text, style string) {
+		for text != "" {
+			if r, size := utf8.DecodeRuneInString(text); unicode.IsSpace(r) {
+				joining = false
+				text = text[size:]
+				continue
+			}
+			end := strings.IndexFunc(text, unicode.IsSpace)
+			if end < 0 {
+				end = len(text)
+			}
+			run := markdownRun{text: text[:end], style: style}
+			if joining {
+				words[len(words)-1] = append(words[len(words)-1], run)
+			} else {
+				words = append(words, markdownWord{run})
+			}
+			joining = true
+			text = text[end:]
+		}
+	}
+	last := 0
+	for _, match := range markdownInline.FindAllStringSubmatchIndex(text, -1) {
+		add(text[last:match[0]], "")
+		last = match[1]
+		group := func(n int) string {
+			if match[n*2] < 0 {
+				return ""
+			}
+			return text[match[n*2]:match[n*2+1]]
+		}
+		switch {
+		case group(1) != "":
+			add(group(1), ansiBold)
+		case group(2) != "":
+			add(group(2), ansiBold)
+		case group(3) != "":
+			add(group(3), ansiItalic)
+		case group(4) != "":
+			add(group(4), ansiItalic)
+		case group(5) != "":
+			add(group(5), ansiCode)
+		case group(6) != "":
+			add(group(6), ansiUnderline)
+			if url := group(7); url != group(6) {
+				add(" ("+url+")", "")
+			}
+		case group(8) != "":
+			add(group(8), ansiUnderline)
+		}
+	}
+	add(text[last:], "")
+	return words
+}
+
+// Wrap words to width, prefixing the first line with "first" and subsequent lines with "rest".
+func wrapMarkdown(words []markdownWord, width int, first, rest string, colour bool) (lines []string) {
+	line := first
+	lineLen := utf8.RuneCountInString(first)
+	empty := true
+	for _, word := range words {
+		text, length := word.render(colour)
+		if !empty && lineLen+1+length > width {
+			lines = append(lines, line)
+			line = rest
+			lineLen = utf8.RuneCountInString(rest)
+			empty = true
+		}
+		if !empty {
+			line += " "
+			lineLen++
+		}
+		line += text
+		lineLen += length
+		empty = false
+	}
+	if !empty || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,56 @@
+package kong
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	text := `
+# Usage
+
+Some **bold** text, some *emphasis*, some ` + "`code`" + ` and
+a [link](https://example.com).
+
+- first item
+  continued
+- second item
+  1. nested
+
+` + "```" + `
+$ app --flag
+` + "```" + `
+
+    indented code
+`
+	expected := `Usage
+
+Some bold text, some emphasis, some code and a
+link (https://example.com).
+
+- first item continued
+- second item
+  1. nested
+
+    $ app --flag
+
+    indented code`
+	require.Equal(t, expected, strings.Join(renderMarkdown(text, 50, false), "\n"))
+}
+
+func TestRenderMarkdownColour(t *testing.T) {
+	actual := renderMarkdown("Some **bold**, `code` and <https://example.com>.", 80, true)
+	require.Equal(t, []string{
+		"Some \x1b[1mbold\x1b[0m, \x1b[36mcode\x1b[0m and \x1b[4mhttps://example.com\x1b[0m.",
+	}, actual)
+}
+
+func TestRenderMarkdownWrapsOnVisibleWidth(t *testing.T) {
+	actual := renderMarkdown("**aaaa** **bbbb** cccc", 13, true)
+	require.Equal(t, []string{
+		"\x1b[1maaaa\x1b[0m \x1b[1mbbbb\x1b[0m",
+		"cccc",
+	}, actual)
+}
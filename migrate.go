@@ -0,0 +1,231 @@
+package kong
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// A ConfigMigration upgrades configuration values by one version, modifying them in place.
+//
+// Keys are dot-separated paths into nested values, eg. "server.port".
+type ConfigMigration func(values map[string]interface{}) error
+
+// A MigratableResolver is a Resolver backed by configuration values that can be upgraded by ConfigMigrations.
+//
+// The Resolver returned by the JSON loader is a MigratableResolver.
+type MigratableResolver interface {
+	Resolver
+	// ConfigValues returns the configuration values, which migrations will modify in place.
+	ConfigValues() map[string]interface{}
+	// WriteConfig writes the configuration values in the format they were loaded from.
+	//
+	// Values of flags in app are written in the form the flag parses them from, eg. "quoted" slices with JoinQuoted().
+	WriteConfig(app *Application, w io.Writer) error
+}
+
+type configMigrations struct {
+	versionKey string
+	steps      []ConfigMigration
+}
+
+// A configuration file loaded by Configuration().
+type configFile struct {
+	path     string
+	resolver Resolver
+}
+
+// RenameKey returns a ConfigMigration that renames a key.
+func RenameKey(from, to string) ConfigMigration {
+	return func(values map[string]interface{}) error {
+		value, ok := lookupConfigKey(values, strings.Split(from, "."))
+		if !ok || from == to {
+			return nil
+		}
+		if err := setConfigKey(values, strings.Split(to, "."), value); err != nil {
+			return err
+		}
+		deleteConfigKey(values, strings.Split(from, "."))
+		return nil
+	}
+}
+
+// MoveKey returns a ConfigMigration that moves a key under a scope, such as a command, eg. MoveKey("port", "server")
+// moves {"port": 80} to {"server": {"port": 80}}.
+func MoveKey(key, scope string) ConfigMigration {
+	parts := strings.Split(key, ".")
+	return RenameKey(key, scope+"."+parts[len(parts)-1])
+}
+
+// TransformValue returns a ConfigMigration that replaces the value of a key with the result of transform.
+func TransformValue(key string, transform func(value interface{}) (interface{}, error)) ConfigMigration {
+	return func(values map[string]interface{}) error {
+		path := strings.Split(key, ".")
+		value, ok := lookupConfigKey(values, path)
+		if !ok {
+			return nil
+		}
+		value, err := transform(value)
+		if err != nil {
+			return errors.Wrap(err, key)
+		}
+		return setConfigKey(values, path, value)
+	}
+}
+
+// Set a path of keys in nested configuration values, creating intermediate maps as required.
+func setConfigKey(values map[string]interface{}, key []string, value interface{}) error {
+	for i, part := range key[:len(key)-1] {
+		next, ok := values[part]
+		if !ok {
+			next = map[string]interface{}{}
+			values[part] = next
+		}
+		if values, ok = next.(map[string]interface{}); !ok {
+			return errors.Errorf("%s is not a map", strings.Join(key[:i+1], "."))
+		}
+	}
+	values[key[len(key)-1]] = value
+	return nil
+}
+
+// Delete a path of keys from nested configuration values.
+func deleteConfigKey(values map[string]interface{}, key []string) {
+	for _, part := range key[:len(key)-1] {
+		var ok bool
+		if values, ok = values[part].(map[string]interface{}); !ok {
+			return
+		}
+	}
+	delete(values, key[len(key)-1])
+}
+
+// Return the version of configuration values, which is 0 if missing.
+func configVersion(values map[string]interface{}, key string) (int, error) {
+	switch version := values[key].(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return int(version), nil
+	case int:
+		return version, nil
+	case string:
+		n, err := strconv.Atoi(version)
+		if err != nil {
+			return 0, errors.Errorf("invalid configuration version %q", version)
+		}
+		return n, nil
+	default:
+		return 0, errors.Errorf("invalid configuration version %v", version)
+	}
+}
+
+// Apply registered ConfigMigrations to a resolver loaded from a configuration file.
+//
+// Returns the version the configuration was migrated from, and true if it was migrated.
+func (k *Kong) migrateConfig(resolver Resolver) (int, bool, error) {
+	migratable, ok := resolver.(MigratableResolver)
+	if k.migrations == nil || !ok {
+		return 0, false, nil
+	}
+	values := migratable.ConfigValues()
+	current := len(k.migrations.steps)
+	version, err := configVersion(values, k.migrations.versionKey)
+	if err != nil {
+		return 0, false, err
+	}
+	if version > current {
+		return 0, false, errors.Errorf("configuration version %d is newer than the supported version %d", version, current)
+	}
+	if version == current {
+		return version, false, nil
+	}
+	for i := version; i < current; i++ {
+		if err := k.migrations.steps[i](values); err != nil {
+			return 0, false, errors.Wrapf(err, "failed to migrate configuration from version %d to %d", i, i+1)
+		}
+	}
+	values[k.migrations.versionKey] = current
+	return version, true, nil
+}
+
+// Apply registered ConfigMigrations to the resolver loaded from path, warning if it was migrated.
+//
+// The version key is then removed so that it is not resolved as a flag, eg. a VersionFlag.
+func (k *Kong) applyConfigMigrations(path string, resolver Resolver) error {
+	version, migrated, err := k.migrateConfig(resolver)
+	if err != nil {
+		return errors.Wrap(err, path)
+	}
+	if migrated {
+		k.warnMigrated(path, version)
+	}
+	if migratable, ok := resolver.(MigratableResolver); ok && k.migrations != nil {
+		delete(migratable.ConfigValues(), k.migrations.versionKey)
+	}
+	return nil
+}
+
+// MigrateConfig applies ConfigMigrations to the configuration file at path, rewriting it if it was migrated.
+//
+// Returns true if the file was rewritten.
+func (k *Kong) MigrateConfig(path string) (bool, error) {
+	if k.loader == nil {
+		return false, errors.New("no configuration loader, use the Configuration() option")
+	}
+	path, err := interpolate(path, k.vars, nil)
+	if err != nil {
+		return false, err
+	}
+	path = ExpandPath(path)
+	r, err := os.Open(path) // nolint: gas
+	if err != nil {
+		return false, err
+	}
+	resolver, err := k.loader(r)
+	_ = r.Close()
+	if err != nil {
+		return false, err
+	}
+	migratable, ok := resolver.(MigratableResolver)
+	if !ok {
+		return false, errors.Errorf("%s: configuration loader does not support migrations", path)
+	}
+	_, migrated, err := k.migrateConfig(resolver)
+	if err != nil || !migrated {
+		return false, errors.Wrap(err, path)
+	}
+	w, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".")
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	defer os.Remove(w.Name()) // nolint: errcheck
+	if err = migratable.WriteConfig(k.Model, w); err != nil {
+		_ = w.Close()
+		return false, err
+	}
+	if err = w.Close(); err != nil {
+		return false, errors.WithStack(err)
+	}
+	if info, err := os.Stat(path); err == nil {
+		_ = os.Chmod(w.Name(), info.Mode())
+	}
+	return true, errors.WithStack(os.Rename(w.Name(), path))
+}
+
+// Warn that the configuration file at path was migrated from an old version.
+func (k *Kong) warnMigrated(path string, version int) {
+	name := filepath.Base(os.Args[0])
+	if k.Model != nil {
+		name = k.Model.Name
+	}
+	msg := fmt.Sprintf("configuration file %s is version %d and was migrated to version %d, please update it",
+		path, version, len(k.migrations.steps))
+	formatMultilineMessage(k.Stderr, []string{name, "warning"}, "%s", msg)
+}
@@ -0,0 +1,197 @@
+package kong_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/alecthomas/kong"
+)
+
+var testMigrations = []kong.ConfigMigration{
+	// Version 0 -> 1: "addr" was renamed to "listen".
+	kong.RenameKey("addr", "listen"),
+	// Version 1 -> 2: "listen" moved under the "serve" command, and "timeout" changed from seconds to a duration.
+	kong.MoveKey("listen", "serve"),
+	kong.TransformValue("timeout", func(value interface{}) (interface{}, error) {
+		seconds, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("expected a number of seconds but got %v", value)
+		}
+		return fmt.Sprintf("%.0fs", seconds), nil
+	}),
+}
+
+func writeConfig(t *testing.T, content string) (string, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "kong-test-")
+	require.NoError(t, err)
+	path := filepath.Join(dir, "config.json")
+	err = ioutil.WriteFile(path, []byte(content), 0600)
+	require.NoError(t, err)
+	return path, func() { os.RemoveAll(dir) }
+}
+
+type migrateCLI struct {
+	Timeout string `config:"timeout"`
+	Serve   struct {
+		Listen string `config:"serve.listen"`
+	} `cmd:""`
+}
+
+func TestConfigMigrations(t *testing.T) {
+	path, cleanup := writeConfig(t, `{"addr": ":8080", "timeout": 30}`)
+	defer cleanup()
+	var cli migrateCLI
+	stderr := &bytes.Buffer{}
+	p := mustNew(t, &cli,
+		kong.Writers(&bytes.Buffer{}, stderr),
+		kong.ConfigMigrations("", testMigrations...),
+		kong.Configuration(kong.JSON, path))
+	_, err := p.Parse([]string{"serve"})
+	require.NoError(t, err)
+	require.Equal(t, ":8080", cli.Serve.Listen)
+	require.Equal(t, "30s", cli.Timeout)
+	require.Contains(t, stderr.String(), "warning: configuration file "+path+" is version 0 and was migrated to version 3, please update it")
+}
+
+func TestConfigMigrationsCurrentVersion(t *testing.T) {
+	path, cleanup := writeConfig(t, `{"version": 3, "serve": {"listen": ":80"}, "timeout": "1m"}`)
+	defer cleanup()
+	var cli migrateCLI
+	stderr := &bytes.Buffer{}
+	p := mustNew(t, &cli,
+		kong.Writers(&bytes.Buffer{}, stderr),
+		kong.ConfigMigrations("", testMigrations...),
+		kong.Configuration(kong.JSON, path))
+	_, err := p.Parse([]string{"serve"})
+	require.NoError(t, err)
+	require.Equal(t, ":80", cli.Serve.Listen)
+	require.Equal(t, "1m", cli.Timeout)
+	require.Empty(t, stderr.String())
+}
+
+func TestConfigMigrationsPartial(t *testing.T) {
+	path, cleanup := writeConfig(t, `{"schema": "2", "serve": {"listen": ":80"}, "timeout": 5}`)
+	defer cleanup()
+	var cli migrateCLI
+	p := mustNew(t, &cli,
+		kong.Writers(&bytes.Buffer{}, &bytes.Buffer{}),
+		kong.ConfigMigrations("schema", testMigrations...),
+		kong.Configuration(kong.JSON, path))
+	_, err := p.Parse([]string{"serve"})
+	require.NoError(t, err)
+	require.Equal(t, ":80", cli.Serve.Listen)
+	require.Equal(t, "5s", cli.Timeout)
+}
+
+func TestConfigMigrationsNewerVersion(t *testing.T) {
+	path, cleanup := writeConfig(t, `{"version": 4}`)
+	defer cleanup()
+	var cli migrateCLI
+	_, err := kong.New(&cli,
+		kong.ConfigMigrations("", testMigrations...),
+		kong.Configuration(kong.JSON, path))
+	require.EqualError(t, err, path+": configuration version 4 is newer than the supported version 3")
+}
+
+func TestConfigMigrationsError(t *testing.T) {
+	path, cleanup := writeConfig(t, `{"version": 2, "timeout": "soon"}`)
+	defer cleanup()
+	var cli migrateCLI
+	_, err := kong.New(&cli,
+		kong.ConfigMigrations("", testMigrations...),
+		kong.Configuration(kong.JSON, path))
+	require.EqualError(t, err, path+": failed to migrate configuration from version 2 to 3: timeout: expected a number of seconds but got soon")
+}
+
+func TestMigrateConfig(t *testing.T) {
+	path, cleanup := writeConfig(t, `{"addr": ":8080", "timeout": 30}`)
+	defer cleanup()
+	var cli migrateCLI
+	p := mustNew(t, &cli,
+		kong.ConfigMigrations("", testMigrations...),
+		kong.Configuration(kong.JSON))
+	migrated, err := p.MigrateConfig(path)
+	require.NoError(t, err)
+	require.True(t, migrated)
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"version": 3, "serve": {"listen": ":8080"}, "timeout": "30s"}`, string(data))
+
+	migrated, err = p.MigrateConfig(path)
+	require.NoError(t, err)
+	require.False(t, migrated)
+}
+
+func TestMigrateConfigQuotedSlice(t *testing.T) {
+	path, cleanup := writeConfig(t, `{"hosts": ["a,b", "c"], "ports": ["80", "443"]}`)
+	defer cleanup()
+	var cli struct {
+		Hosts []string `quoted:""`
+		Ports []string
+	}
+	p := mustNew(t, &cli,
+		kong.ConfigMigrations("", kong.RenameKey("unused", "unused")),
+		kong.Configuration(kong.JSON))
+	migrated, err := p.MigrateConfig(path)
+	require.NoError(t, err)
+	require.True(t, migrated)
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"version": 1, "hosts": "\"a,b\",c", "ports": ["80", "443"]}`, string(data))
+
+	p = mustNew(t, &cli, kong.ConfigMigrations("", kong.RenameKey("unused", "unused")), kong.Configuration(kong.JSON, path))
+	_, err = p.Parse(nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a,b", "c"}, cli.Hosts)
+}
+
+func TestMoveKeyConflict(t *testing.T) {
+	values := map[string]interface{}{"listen": ":80", "serve": "yes"}
+	err := kong.MoveKey("listen", "serve")(values)
+	require.EqualError(t, err, "serve is not a map")
+}
+
+func TestConfigMigrationsVersionFlag(t *testing.T) {
+	path, cleanup := writeConfig(t, `{"version": 1, "listen": ":80"}`)
+	defer cleanup()
+	var cli struct {
+		migrateCLI
+		Version kong.VersionFlag
+	}
+	p := mustNew(t, &cli,
+		kong.Writers(&bytes.Buffer{}, &bytes.Buffer{}),
+		kong.ConfigMigrations("", testMigrations...),
+		kong.Configuration(kong.JSON, path))
+	_, err := p.Parse([]string{"serve"})
+	require.NoError(t, err)
+	require.Equal(t, ":80", cli.Serve.Listen)
+	require.False(t, bool(cli.Version))
+}
+
+func TestMigrateConfigWithoutLoader(t *testing.T) {
+	var cli migrateCLI
+	p := mustNew(t, &cli, kong.ConfigMigrations("", testMigrations...))
+	_, err := p.MigrateConfig("config.json")
+	require.EqualError(t, err, "no configuration loader, use the Configuration() option")
+}
+
+func TestConfigMigrationsAfterConfiguration(t *testing.T) {
+	path, cleanup := writeConfig(t, `{"addr": ":8080", "timeout": 30}`)
+	defer cleanup()
+	var cli migrateCLI
+	p := mustNew(t, &cli,
+		kong.Writers(&bytes.Buffer{}, &bytes.Buffer{}),
+		kong.Configuration(kong.JSON, path),
+		kong.ConfigMigrations("", testMigrations...))
+	_, err := p.Parse([]string{"serve"})
+	require.NoError(t, err)
+	require.Equal(t, ":8080", cli.Serve.Listen)
+	require.Equal(t, "30s", cli.Timeout)
+}
@@ -21,6 +21,8 @@ type Application struct {
 	*Node
 	// Help flag, if the NoDefaultHelp() option is not specified.
 	HelpFlag *Flag
+	// Help command, if the HelpCommand() option is specified.
+	HelpCommand *Command
 }
 
 // Argument represents a branching positional argument.
@@ -55,6 +57,16 @@ type Node struct {
 	Tag        *Tag
 
 	Argument *Value // Populated when Type is ArgumentNode.
+
+	RequiredFlags []string // Names of inherited flags required by this command, see RequiredFlagsProvider.
+}
+
+// RequiredFlagsProvider can be implemented by commands to declare inherited flags that they require.
+//
+// Required flags also apply to subcommands.
+type RequiredFlagsProvider interface {
+	// RequiredFlags returns the names of the required flags, without leading hyphens.
+	RequiredFlags() []string
 }
 
 func (*Node) node() {}
@@ -76,7 +88,7 @@ func (n *Node) Find(ptr interface{}) *Node {
 }
 
 func (n *Node) findNode(key reflect.Value) *Node {
-	if n.Target == key {
+	if n.Target.CanAddr() && n.Target.Addr().Interface() == key.Interface() {
 		return n
 	}
 	for _, child := range n.Children {
@@ -163,7 +175,7 @@ func (n *Node) FlagSummary(hide bool) string {
 	for _, group := range n.AllFlags(hide) {
 		for _, flag := range group {
 			count++
-			if flag.Required {
+			if n.RequiresFlag(flag) && !flag.Tag.NoCLI {
 				required = append(required, flag.Summary())
 			}
 		}
@@ -171,6 +183,32 @@ func (n *Node) FlagSummary(hide bool) string {
 	return strings.Join(required, " ")
 }
 
+// RequiresFlag returns true if flag is required when this Node is selected.
+//
+// This is the case if the flag is always required, or if the Path() of this Node or one of its ancestors is listed in the
+// flag's "requiredfor" tag, or if one of them declares the flag via RequiredFlagsProvider.
+func (n *Node) RequiresFlag(flag *Flag) bool {
+	if flag.Required {
+		return true
+	}
+	for node := n; node != nil; node = node.Parent {
+		if node.Type == ApplicationNode {
+			continue
+		}
+		for _, path := range flag.Tag.RequiredFor {
+			if path == node.Path() {
+				return true
+			}
+		}
+		for _, name := range node.RequiredFlags {
+			if name == flag.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // FullPath is like Path() but includes the Application root node.
 func (n *Node) FullPath() string {
 	root := n
@@ -206,6 +244,8 @@ func (n *Node) Path() (out string) {
 type Value struct {
 	Flag         *Flag // Nil if positional argument.
 	Name         string
+	Field        string // Name of the struct field this value was built from, eg. "DB.Host" for fields of a named embedded struct.
+	Paired       *Value // For fields that are both a positional argument and a flag, the other of the two.
 	Help         string
 	Default      string
 	DefaultValue reflect.Value
@@ -217,6 +257,8 @@ type Value struct {
 	Set          bool   // Set to true when this value is set through some mechanism.
 	Format       string // Formatting directive, if applicable.
 	Position     int    // Position (for positional arguments).
+
+	envArgs string // Environment variable configured with EnvArgs(), if any.
 }
 
 // EnumMap returns a map of the enums in this value.
@@ -232,6 +274,9 @@ func (v *Value) EnumMap() map[string]bool {
 // ShortSummary returns a human-readable summary of the value, not including any placeholders/defaults.
 func (v *Value) ShortSummary() string {
 	if v.Flag != nil {
+		if v.Flag.ShortOnly {
+			return fmt.Sprintf("-%c", v.Flag.Short)
+		}
 		return fmt.Sprintf("--%s", v.Name)
 	}
 	argText := "<" + v.Name + ">"
@@ -247,10 +292,16 @@ func (v *Value) ShortSummary() string {
 // Summary returns a human-readable summary of the value.
 func (v *Value) Summary() string {
 	if v.Flag != nil {
+		if v.Flag.ShortOnly {
+			if v.IsBool() {
+				return fmt.Sprintf("-%c", v.Flag.Short)
+			}
+			return fmt.Sprintf("-%c %s", v.Flag.Short, v.Flag.FormatPlaceHolder())
+		}
 		if v.IsBool() {
 			return fmt.Sprintf("--%s", v.Name)
 		}
-		return fmt.Sprintf("--%s=%s", v.Name, v.Flag.FormatPlaceHolder())
+		return fmt.Sprintf("--%s%s%s", v.Name, v.Flag.valueSeparator(), v.Flag.FormatPlaceHolder())
 	}
 	argText := "<" + v.Name + ">"
 	if v.IsCumulative() {
@@ -326,7 +377,7 @@ func (v *Value) ApplyDefault() error {
 // Does not include resolvers.
 func (v *Value) Reset() error {
 	v.Target.Set(reflect.Zero(v.Target.Type()))
-	if v.Tag.Env != "" {
+	if v.Tag.Env != "" && !v.Tag.NoEnv {
 		envar := os.Getenv(v.Tag.Env)
 		if envar != "" {
 			err := v.Parse(ScanFromTokens(Token{Type: FlagValueToken, Value: envar}), v.Target)
@@ -342,6 +393,49 @@ func (v *Value) Reset() error {
 	return nil
 }
 
+// Returns true if the value is a positional argument or flag whose paired flag or positional argument was set.
+func (v *Value) setByPaired() bool {
+	return v.Paired != nil && v.Paired.Set
+}
+
+// AllowedSources describes where a value may be set from, eg. "the command-line, $TOKEN, $MYAPP_OPTS or configuration".
+func (v *Value) AllowedSources() string {
+	sources := []string{}
+	if !v.Tag.NoCLI {
+		sources = append(sources, "the command-line")
+	}
+	if v.Tag.Env != "" && !v.Tag.NoEnv {
+		sources = append(sources, "$"+v.Tag.Env)
+	}
+	if v.envArgs != "" && !v.Tag.NoEnv {
+		sources = append(sources, "$"+v.envArgs)
+	}
+	if v.Flag != nil && !v.Tag.NoConfig {
+		sources = append(sources, "configuration")
+	}
+	switch len(sources) {
+	case 0:
+		return ""
+	case 1:
+		return sources[0]
+	}
+	return strings.Join(sources[:len(sources)-1], ", ") + " or " + sources[len(sources)-1]
+}
+
+// Restricted returns true if the value may not be set from one or more sources.
+func (v *Value) Restricted() bool {
+	return v.Tag.NoCLI || v.Tag.NoEnv || v.Tag.NoConfig
+}
+
+// Error for a value set from a source it is restricted from, eg. "on the command-line".
+func (v *Value) restrictedSourceError(source string) error {
+	allowed := v.AllowedSources()
+	if allowed == "" {
+		return fmt.Errorf("%s can't be set %s", v.ShortSummary(), source)
+	}
+	return fmt.Errorf("%s can't be set %s, use %s instead", v.ShortSummary(), source, allowed)
+}
+
 func (*Value) node() {}
 
 // A Positional represents a non-branching command-line positional argument.
@@ -355,16 +449,22 @@ type Flag struct {
 	PlaceHolder string
 	Env         string
 	Short       rune
+	ShortOnly   bool // Flag has no long form. Name is still used to identify the flag, eg. by resolvers.
 	Hidden      bool
 }
 
 func (f *Flag) String() string {
+	if f.ShortOnly {
+		return f.Summary()
+	}
 	out := "--" + f.Name
 	if f.Short != 0 {
 		out = fmt.Sprintf("-%c, %s", f.Short, out)
 	}
 	if !f.IsBool() {
-		out += "=" + f.FormatPlaceHolder()
+		out += f.valueSeparator() + f.FormatPlaceHolder()
+	} else {
+		out += boolValuesHint(f)
 	}
 	return out
 }
@@ -372,7 +472,7 @@ func (f *Flag) String() string {
 // FormatPlaceHolder formats the placeholder string for a Flag.
 func (f *Flag) FormatPlaceHolder() string {
 	tail := ""
-	if f.Value.IsSlice() {
+	if f.Value.IsSlice() && f.Tag.Arity == 0 {
 		tail += ",..."
 	}
 	if f.Default != "" {
@@ -381,6 +481,9 @@ func (f *Flag) FormatPlaceHolder() string {
 		}
 		return f.Default + tail
 	}
+	if f.Tag.Arity > 0 {
+		return f.arityPlaceHolder()
+	}
 	if f.PlaceHolder != "" {
 		return f.PlaceHolder + tail
 	}
@@ -390,6 +493,36 @@ func (f *Flag) FormatPlaceHolder() string {
 	return strings.ToUpper(f.Name) + tail
 }
 
+// Placeholder for each of the values of a flag with an "arity" tag, eg. "OLD NEW".
+func (f *Flag) arityPlaceHolder() string {
+	typ := f.Target.Type()
+	if f.Tag.Has("placeholder") && strings.Contains(f.PlaceHolder, " ") {
+		return f.PlaceHolder
+	}
+	placeholders := make([]string, f.Tag.Arity)
+	for i := range placeholders {
+		switch {
+		case f.Tag.Has("placeholder"):
+			placeholders[i] = f.PlaceHolder
+		case typ.Kind() == reflect.Struct:
+			placeholders[i] = strings.ToUpper(dashedString(typ.Field(i).Name))
+		case typ.Elem().Name() != "":
+			placeholders[i] = strings.ToUpper(dashedString(typ.Elem().Name()))
+		default:
+			placeholders[i] = strings.ToUpper(f.Name)
+		}
+	}
+	return strings.Join(placeholders, " ")
+}
+
+// Separator between a flag and its value when displayed.
+func (f *Flag) valueSeparator() string {
+	if f.Tag.Arity > 0 {
+		return " "
+	}
+	return "="
+}
+
 // This is directly from the Go 1.13 source code.
 func reflectValueIsZero(v reflect.Value) bool {
 	switch v.Kind() {
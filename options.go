@@ -61,6 +61,82 @@ func NoDefaultHelp() Option {
 	})
 }
 
+// ConfigureHelpFlag overrides the long name and short character of the default help flag.
+//
+// A short of 0 disables the short form, eg. ConfigureHelpFlag("help", '?') or ConfigureHelpFlag("help", 0) to
+// free up -h for another flag.
+func ConfigureHelpFlag(name string, short rune) Option {
+	return OptionFunc(func(k *Kong) error {
+		if name == "" {
+			return errors.Errorf("help flag name must not be empty")
+		}
+		k.helpFlagName = name
+		k.helpFlagShort = short
+		return nil
+	})
+}
+
+// HelpCommand adds a top-level "help [<command> ...]" command that displays help for the given command path.
+//
+// This can be combined with NoDefaultHelp() to provide help only through a command.
+func HelpCommand() Option {
+	return OptionFunc(func(k *Kong) error {
+		k.helpCommand = true
+		return nil
+	})
+}
+
+// HelpFor restricts the help flag to the subtrees rooted at the given commands or arguments.
+//
+// Each value must be a pointer to a command or branching argument field in the grammar, eg.
+//
+// 		HelpFor(&cli.Server, &cli.Client)
+//
+// The help flag will not be accepted elsewhere in the command tree.
+func HelpFor(nodes ...interface{}) Option {
+	return OptionFunc(func(k *Kong) error {
+		k.postBuildOptions = append(k.postBuildOptions, OptionFunc(func(k *Kong) error {
+			flag := k.Model.HelpFlag
+			if flag == nil {
+				return errors.Errorf("HelpFor() can not be used with NoDefaultHelp()")
+			}
+			targets := []*Node{}
+			for _, ptr := range nodes {
+				node := k.Model.Find(ptr)
+				if node == nil {
+					return errors.Errorf("HelpFor(): %T is not a command or argument in the grammar", ptr)
+				}
+				targets = append(targets, node)
+			}
+			k.Model.Flags = removeFlag(k.Model.Flags, flag)
+		nextTarget:
+			for _, node := range targets {
+				// Descendants of another target already inherit the flag.
+				for p := node.Parent; p != nil; p = p.Parent {
+					for _, target := range targets {
+						if p == target {
+							continue nextTarget
+						}
+					}
+				}
+				node.Flags = append([]*Flag{flag}, node.Flags...)
+			}
+			return nil
+		}))
+		return nil
+	})
+}
+
+func removeFlag(flags []*Flag, flag *Flag) []*Flag {
+	out := make([]*Flag, 0, len(flags))
+	for _, f := range flags {
+		if f != flag {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
 // PostBuild provides read/write access to kong.Kong after initial construction of the model is complete but before
 // parsing occurs.
 //
@@ -126,6 +202,51 @@ func NamedMapper(name string, mapper Mapper) Option {
 	})
 }
 
+// BoolValues replaces the words accepted for boolean values application-wide, eg.
+//
+// 		BoolValues(BoolVocabulary{"on", "off"}, BoolVocabulary{"enable", "disable"})
+//
+// The first vocabulary is displayed in help. Individual flags can override this with the "boolvalues" tag.
+func BoolValues(vocabularies ...BoolVocabulary) Option {
+	return OptionFunc(func(k *Kong) error {
+		if len(vocabularies) == 0 {
+			return errors.New("at least one boolean vocabulary is required")
+		}
+		mapper := boolMapper{vocabularies: vocabularies}
+		k.registry.RegisterKind(reflect.Bool, mapper).
+			RegisterType(reflect.TypeOf((*bool)(nil)), mapper).
+			RegisterType(reflect.TypeOf(TriBool(0)), mapper)
+		return nil
+	})
+}
+
+// AggregateErrors reports all validation problems, such as missing flags and invalid enum values, as
+// ValidationErrors rather than stopping at the first.
+func AggregateErrors() Option {
+	return OptionFunc(func(k *Kong) error {
+		k.aggregateErrors = true
+		return nil
+	})
+}
+
+// InheritTags declares how tag keys on embedded structs are inherited by their fields, eg. for custom tag keys used by
+// extensions:
+//
+// 		InheritTags(InheritUnlessSet, "feature", "deprecated")
+//
+// This can also override the inheritance of builtin keys, such as "hidden".
+func InheritTags(inheritance TagInheritance, keys ...string) Option {
+	return OptionFunc(func(k *Kong) error {
+		if k.inheritance == nil {
+			k.inheritance = map[string]TagInheritance{}
+		}
+		for _, key := range keys {
+			k.inheritance[key] = inheritance
+		}
+		return nil
+	})
+}
+
 // Writers overrides the default writers. Useful for testing or interactive use.
 func Writers(stdout, stderr io.Writer) Option {
 	return OptionFunc(func(k *Kong) error {
@@ -221,6 +342,46 @@ func UsageOnError() Option {
 	})
 }
 
+// EnvArgs prepends arguments from an environment variable to the command-line, similarly to GOFLAGS or LESS.
+//
+// The value of the variable is split using shell quoting rules. As arguments from the command-line are parsed
+// after those from the environment, they take precedence. Flags of commands selected on the command-line may also be
+// set, but "--" is not allowed.
+//
+// Arguments from the environment are not from the command-line, so may set "nocli" values but not "noenv" values.
+//
+// If name is empty, it defaults to the upper-cased application name suffixed with "_OPTS", eg. MYAPP_OPTS.
+func EnvArgs(name string) Option {
+	return OptionFunc(func(k *Kong) error {
+		k.argsEnv = &name
+		return nil
+	})
+}
+
+// RunOrdering configures the order in which Context.Run() calls Run() methods in the command hierarchy.
+func RunOrdering(order RunOrder) Option {
+	return OptionFunc(func(k *Kong) error {
+		k.runOrder = order
+		return nil
+	})
+}
+
+// A Preprocessor rewrites command-line tokens before they are parsed.
+//
+// Tokens are initially untyped, with one per command-line argument. Tokens derived from an argument should retain its
+// Position, so that errors can refer to the original argument.
+type Preprocessor func(tokens []Token) ([]Token, error)
+
+// Preprocessors registers Preprocessors to rewrite the command-line before it is parsed. They are applied in order.
+//
+// This can be used to support legacy syntaxes, eg. rewriting "-j4" to "--jobs=4".
+func Preprocessors(preprocessors ...Preprocessor) Option {
+	return OptionFunc(func(k *Kong) error {
+		k.preprocessors = append(k.preprocessors, preprocessors...)
+		return nil
+	})
+}
+
 // ClearResolvers clears all existing resolvers.
 func ClearResolvers() Option {
 	return OptionFunc(func(k *Kong) error {
@@ -237,6 +398,31 @@ func Resolvers(resolvers ...Resolver) Option {
 	})
 }
 
+// Decryption registers a Decrypter for resolved string values starting with "prefix".
+//
+// eg. Decryption("enc:", decrypter) will decrypt the configuration value "enc:<ciphertext>". Decrypted values are
+// treated as secrets, and will not be included in error messages.
+func Decryption(prefix string, decrypter Decrypter) Option {
+	return OptionFunc(func(k *Kong) error {
+		if prefix == "" {
+			return errors.Errorf("decryption prefix must not be empty")
+		}
+		k.decrypters = append(k.decrypters, prefixedDecrypter{prefix: prefix, decrypter: decrypter})
+		return nil
+	})
+}
+
+// ExpandResolvedValues enables expansion of variables in string values returned by resolvers.
+//
+// Variables are in the form ${name} or ${name=default}, and are looked up in Vars then the environment, eg.
+// "${HOME}/.cache/app". A literal "$" can be escaped as "$$".
+func ExpandResolvedValues() Option {
+	return OptionFunc(func(k *Kong) error {
+		k.expandResolved = true
+		return nil
+	})
+}
+
 // ConfigurationLoader is a function that builds a resolver from a file.
 type ConfigurationLoader func(r io.Reader) (Resolver, error)
 
@@ -246,16 +432,38 @@ type ConfigurationLoader func(r io.Reader) (Resolver, error)
 //
 // Note: The JSON function is a ConfigurationLoader.
 //
-// ~ and variable expansion will occur on the provided paths.
+// ~ and variable expansion will occur on the provided paths. Variables are interpolated first, so that they may hold
+// absolute paths, eg. "${config_dir}/app.json".
 func Configuration(loader ConfigurationLoader, paths ...string) Option {
 	return OptionFunc(func(k *Kong) error {
 		k.loader = loader
 		for _, path := range paths {
-			resolver, _ := k.LoadConfig(path)
-			if resolver != nil {
-				k.resolvers = append(k.resolvers, resolver)
+			resolver, _ := k.openConfig(path)
+			if resolver == nil {
+				continue
 			}
+			k.configFiles = append(k.configFiles, configFile{path: path, resolver: resolver})
+			k.resolvers = append(k.resolvers, resolver)
+		}
+		return nil
+	})
+}
+
+// ConfigMigrations registers functions to upgrade old configuration files, applied by Configuration and
+// LoadConfig before resolution.
+//
+// The version of a configuration file is read from versionKey, which defaults to "version", and is 0 if missing.
+// migrations[i] upgrades a file from version i to version i+1, so the current version is len(migrations). A warning
+// asking the user to update their file is written to stderr when a file is migrated, and Kong.MigrateConfig can be
+// used to rewrite it. Files newer than the current version are an error.
+//
+// The version key is reserved for the version, and is not resolved as a flag.
+func ConfigMigrations(versionKey string, migrations ...ConfigMigration) Option {
+	return OptionFunc(func(k *Kong) error {
+		if versionKey == "" {
+			versionKey = "version"
 		}
+		k.migrations = &configMigrations{versionKey: versionKey, steps: migrations}
 		return nil
 	})
 }
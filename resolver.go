@@ -17,6 +17,19 @@ type Resolver interface {
 	Resolve(context *Context, parent *Path, flag *Flag) (interface{}, error)
 }
 
+// A BatchResolver is a Resolver that can resolve all the flags of a Path in a single call.
+//
+// Context.Resolve() calls ResolveAll() at most once per Path for each parse, in place of calling Resolve() for
+// each flag. This is useful for expensive sources, such as remote services or external commands.
+type BatchResolver interface {
+	Resolver
+
+	// ResolveAll resolves values for flags, which have not been set on the command-line.
+	//
+	// Flags without a value should be omitted from the returned map.
+	ResolveAll(context *Context, parent *Path, flags []*Flag) (map[*Flag]interface{}, error)
+}
+
 // ResolverFunc is a convenience type for non-validating Resolvers.
 type ResolverFunc func(context *Context, parent *Path, flag *Flag) (interface{}, error)
 
@@ -27,23 +40,211 @@ func (r ResolverFunc) Resolve(context *Context, parent *Path, flag *Flag) (inter
 }
 func (r ResolverFunc) Validate(app *Application) error { return nil } //  nolint: golint
 
+// A KeyStrategy maps a flag to the path of its key in configuration, which usually has a single element.
+type KeyStrategy func(flag *Flag) []string
+
+// Built-in KeyStrategy implementations.
+var (
+	// KebabCaseKeys uses the flag name, eg. "cache-dir".
+	KebabCaseKeys KeyStrategy = func(flag *Flag) []string { return []string{flag.Name} }
+	// SnakeCaseKeys replaces hyphens in the flag name with underscores, eg. "cache_dir".
+	SnakeCaseKeys KeyStrategy = func(flag *Flag) []string {
+		return []string{strings.Replace(flag.Name, "-", "_", -1)}
+	}
+	// CamelCaseKeys converts the flag name to camelCase, eg. "cacheDir".
+	CamelCaseKeys KeyStrategy = func(flag *Flag) []string {
+		parts := strings.Split(flag.Name, "-")
+		for i := 1; i < len(parts); i++ {
+			if parts[i] != "" {
+				parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+			}
+		}
+		return []string{strings.Join(parts, "")}
+	}
+	// FieldNameKeys uses the name of the Go struct field, eg. "CacheDir". Fields of named embedded structs are nested
+	// under the name of the embedding field, eg. {"DB": {"Host": ...}}, as with encoding/json.
+	FieldNameKeys KeyStrategy = func(flag *Flag) []string { return strings.Split(flag.Field, ".") }
+)
+
+// ConfigKey returns the path of keys used to look up flag in configuration.
+//
+// This is the dot-separated path in the flag's "config" tag if present, otherwise the path from strategy. Flag names
+// containing dots, eg. "log.level", are not split.
+func ConfigKey(flag *Flag, strategy KeyStrategy) []string {
+	if flag.Tag.Config != "" {
+		return strings.Split(flag.Tag.Config, ".")
+	}
+	return strategy(flag)
+}
+
+// A KeyedResolver is a Resolver that can report the configuration key it resolves a flag from.
+//
+// This is used to name the key in error messages.
+type KeyedResolver interface {
+	Resolver
+	ResolverKey(flag *Flag) string
+}
+
+// Name the source of a flag's value in resolver, for error messages.
+func resolverKey(resolver Resolver, flag *Flag) string {
+	if keyed, ok := resolver.(KeyedResolver); ok {
+		return keyed.ResolverKey(flag)
+	}
+	return flag.ShortSummary()
+}
+
+// Look up a path of keys in nested configuration values.
+func lookupConfigKey(values map[string]interface{}, key []string) (interface{}, bool) {
+	for i, part := range key {
+		value, ok := values[part]
+		if !ok {
+			return nil, false
+		}
+		if i == len(key)-1 {
+			return value, true
+		}
+		if values, ok = value.(map[string]interface{}); !ok {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
 // JSON returns a Resolver that retrieves values from a JSON source.
 //
-// Hyphens in flag names are replaced with underscores.
+// Hyphens in flag names are replaced with underscores. See JSONWithKeys() for other key strategies.
 func JSON(r io.Reader) (Resolver, error) {
-	values := map[string]interface{}{}
-	err := json.NewDecoder(r).Decode(&values)
+	return JSONWithKeys(SnakeCaseKeys)(r)
+}
+
+// JSONWithKeys returns a ConfigurationLoader for JSON sources, mapping flags to keys with strategy.
+//
+// eg. Configuration(JSONWithKeys(CamelCaseKeys), "~/.myapp.json")
+func JSONWithKeys(strategy KeyStrategy) ConfigurationLoader {
+	return func(r io.Reader) (Resolver, error) {
+		values := map[string]interface{}{}
+		err := json.NewDecoder(r).Decode(&values)
+		if err != nil {
+			return nil, err
+		}
+		return &jsonResolver{values: values, strategy: strategy}, nil
+	}
+}
+
+type jsonResolver struct {
+	values   map[string]interface{}
+	strategy KeyStrategy
+}
+
+func (j *jsonResolver) Validate(app *Application) error { return nil }
+
+func (j *jsonResolver) Resolve(context *Context, parent *Path, flag *Flag) (interface{}, error) {
+	raw, ok := lookupConfigKey(j.values, ConfigKey(flag, j.strategy))
+	if !ok {
+		return nil, nil
+	}
+	return raw, nil
+}
+
+func (j *jsonResolver) ResolverKey(flag *Flag) string {
+	return strings.Join(ConfigKey(flag, j.strategy), ".")
+}
+
+func (j *jsonResolver) ConfigValues() map[string]interface{} { return j.values }
+
+// WriteConfig writes the values with the keys they were loaded from, which are in the resolver's KeyStrategy except
+// where renamed by migrations.
+//
+// Lists of strings for "quoted" slice flags are written as a single string joined with JoinQuoted().
+func (j *jsonResolver) WriteConfig(app *Application, w io.Writer) error {
+	err := Visit(app, func(node Visitable, next Next) error {
+		if flag, ok := node.(*Flag); ok && flag.Tag.Quoted && flag.IsSlice() && flag.Tag.Sep != -1 {
+			key := ConfigKey(flag, j.strategy)
+			if value, ok := lookupConfigKey(j.values, key); ok {
+				if list, ok := configStrings(value); ok {
+					if err := setConfigKey(j.values, key, JoinQuoted(list, flag.Tag.Sep)); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return next(nil)
+	})
 	if err != nil {
-		return nil, err
+		return err
 	}
-	var f ResolverFunc = func(context *Context, parent *Path, flag *Flag) (interface{}, error) {
-		name := strings.Replace(flag.Name, "-", "_", -1)
-		raw, ok := values[name]
-		if !ok {
-			return nil, nil
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(j.values)
+}
+
+// Convert a configuration value to a list of strings, if it is one.
+func configStrings(value interface{}) ([]string, bool) {
+	switch value := value.(type) {
+	case []string:
+		return value, true
+	case []interface{}:
+		out := make([]string, len(value))
+		for i, el := range value {
+			s, ok := el.(string)
+			if !ok {
+				return nil, false
+			}
+			out[i] = s
 		}
-		return raw, nil
+		return out, true
+	default:
+		return nil, false
 	}
+}
 
-	return f, nil
+// A resolver that only resolves the flags of a command and its subcommands.
+type scopedResolver struct {
+	resolver Resolver
+	flags    map[*Flag]bool
+}
+
+// A scopedResolver wrapping a BatchResolver, resolving the flags of a Path that are in scope with a single call.
+type scopedBatchResolver struct {
+	*scopedResolver
+	batch BatchResolver
+}
+
+func newScopedResolver(resolver Resolver, node *Node) Resolver {
+	flags := map[*Flag]bool{}
+	_ = Visit(node, func(node Visitable, next Next) error {
+		if flag, ok := node.(*Flag); ok {
+			flags[flag] = true
+		}
+		return next(nil)
+	})
+	scoped := &scopedResolver{resolver: resolver, flags: flags}
+	if batch, ok := resolver.(BatchResolver); ok {
+		return &scopedBatchResolver{scopedResolver: scoped, batch: batch}
+	}
+	return scoped
+}
+
+func (s *scopedResolver) Validate(app *Application) error { return s.resolver.Validate(app) }
+
+func (s *scopedResolver) ResolverKey(flag *Flag) string { return resolverKey(s.resolver, flag) }
+
+func (s *scopedResolver) Resolve(context *Context, parent *Path, flag *Flag) (interface{}, error) {
+	if !s.flags[flag] {
+		return nil, nil
+	}
+	return s.resolver.Resolve(context, parent, flag)
+}
+
+func (s *scopedBatchResolver) ResolveAll(context *Context, parent *Path, flags []*Flag) (map[*Flag]interface{}, error) {
+	scoped := []*Flag{}
+	for _, flag := range flags {
+		if s.flags[flag] {
+			scoped = append(scoped, flag)
+		}
+	}
+	if len(scoped) == 0 {
+		return nil, nil
+	}
+	return s.batch.ResolveAll(context, parent, scoped)
 }
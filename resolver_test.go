@@ -243,3 +243,203 @@ func TestValidatingResolverErrors(t *testing.T) {
 	_, err := mustNew(t, &cli, kong.Resolvers(resolver)).Parse(nil)
 	require.EqualError(t, err, "invalid")
 }
+
+func TestExpandResolvedValues(t *testing.T) {
+	restoreEnv := tempEnv(envMap{"KONG_TEST_HOME": "/home/kong"})
+	defer restoreEnv()
+
+	var cli struct {
+		CacheDir string
+		Name     string
+		Price    string
+		Paths    []string
+	}
+	r, err := kong.JSON(strings.NewReader(`{
+		"cache_dir": "${KONG_TEST_HOME}/.cache/${app}",
+		"name": "${missing=default}",
+		"price": "$$${amount}",
+		"paths": ["${KONG_TEST_HOME}/a", "b"]
+	}`))
+	require.NoError(t, err)
+	_, err = mustNew(t, &cli, kong.Resolvers(r), kong.ExpandResolvedValues(), kong.Vars{"app": "test", "amount": "10"}).Parse(nil)
+	require.NoError(t, err)
+	require.Equal(t, "/home/kong/.cache/test", cli.CacheDir)
+	require.Equal(t, "default", cli.Name)
+	require.Equal(t, "$10", cli.Price)
+	require.Equal(t, []string{"/home/kong/a", "b"}, cli.Paths)
+}
+
+func TestExpandResolvedValuesIsOptIn(t *testing.T) {
+	var cli struct {
+		CacheDir string
+	}
+	r, err := kong.JSON(strings.NewReader(`{"cache_dir": "${HOME}/.cache"}`))
+	require.NoError(t, err)
+	_, err = mustNew(t, &cli, kong.Resolvers(r)).Parse(nil)
+	require.NoError(t, err)
+	require.Equal(t, "${HOME}/.cache", cli.CacheDir)
+}
+
+func TestExpandResolvedMapValues(t *testing.T) {
+	var cli struct {
+		Paths map[string]string
+	}
+	r, err := kong.JSON(strings.NewReader(`{"paths": {"cache": "${root}/cache", "data": "$${root}"}}`))
+	require.NoError(t, err)
+	_, err = mustNew(t, &cli, kong.Resolvers(r), kong.ExpandResolvedValues(), kong.Vars{"root": "/srv"}).Parse(nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"cache": "/srv/cache", "data": "${root}"}, cli.Paths)
+}
+
+func TestExpandResolvedValuesNestedKey(t *testing.T) {
+	var cli struct {
+		Dir string `config:"server.dir"`
+	}
+	r, err := kong.JSON(strings.NewReader(`{"server": {"dir": "${KONG_TEST_UNDEFINED}"}}`))
+	require.NoError(t, err)
+	_, err = mustNew(t, &cli, kong.Resolvers(r), kong.ExpandResolvedValues()).Parse(nil)
+	require.EqualError(t, err, "server.dir: failed to expand configuration value: undefined variable ${KONG_TEST_UNDEFINED}")
+}
+
+func TestExpandResolvedValuesUndefined(t *testing.T) {
+	var cli struct {
+		CacheDir string
+	}
+	r, err := kong.JSON(strings.NewReader(`{"cache_dir": "${KONG_TEST_UNDEFINED}/.cache"}`))
+	require.NoError(t, err)
+	_, err = mustNew(t, &cli, kong.Resolvers(r), kong.ExpandResolvedValues()).Parse(nil)
+	require.EqualError(t, err, "cache_dir: failed to expand configuration value: undefined variable ${KONG_TEST_UNDEFINED}")
+}
+
+func TestJSONKeyStrategies(t *testing.T) {
+	type config struct {
+		CacheDir string
+	}
+	tests := []struct {
+		strategy kong.KeyStrategy
+		json     string
+	}{
+		{kong.KebabCaseKeys, `{"cache-dir": "/tmp"}`},
+		{kong.SnakeCaseKeys, `{"cache_dir": "/tmp"}`},
+		{kong.CamelCaseKeys, `{"cacheDir": "/tmp"}`},
+		{kong.FieldNameKeys, `{"CacheDir": "/tmp"}`},
+	}
+	for _, test := range tests {
+		// nolint: scopelint
+		t.Run(test.json, func(t *testing.T) {
+			var cli config
+			r, err := kong.JSONWithKeys(test.strategy)(strings.NewReader(test.json))
+			require.NoError(t, err)
+			_, err = mustNew(t, &cli, kong.Resolvers(r)).Parse(nil)
+			require.NoError(t, err)
+			require.Equal(t, "/tmp", cli.CacheDir)
+		})
+	}
+}
+
+func TestJSONDottedFlagNameIsNotNested(t *testing.T) {
+	var cli struct {
+		LogLevel string `name:"log.level"`
+	}
+	r, err := kong.JSON(strings.NewReader(`{"log.level": "debug"}`))
+	require.NoError(t, err)
+	_, err = mustNew(t, &cli, kong.Resolvers(r)).Parse(nil)
+	require.NoError(t, err)
+	require.Equal(t, "debug", cli.LogLevel)
+}
+
+func TestJSONFieldNameKeysIncludeEmbeddingPath(t *testing.T) {
+	type server struct {
+		Host string
+	}
+	var cli struct {
+		DB     server `embed:"" prefix:"db-"`
+		Server server `embed:"" prefix:"server-"`
+	}
+	r, err := kong.JSONWithKeys(kong.FieldNameKeys)(strings.NewReader(`{"DB": {"Host": "db"}, "Server": {"Host": "web"}}`))
+	require.NoError(t, err)
+	_, err = mustNew(t, &cli, kong.Resolvers(r)).Parse(nil)
+	require.NoError(t, err)
+	require.Equal(t, "db", cli.DB.Host)
+	require.Equal(t, "web", cli.Server.Host)
+}
+
+func TestJSONConfigTag(t *testing.T) {
+	var cli struct {
+		Port    int    `config:"server.port"`
+		Host    string `config:"server.host"`
+		Verbose bool   `config:"debug"`
+		Missing string `config:"server.missing.key"`
+	}
+	r, err := kong.JSON(strings.NewReader(`{"server": {"port": 8080, "host": "localhost"}, "debug": true}`))
+	require.NoError(t, err)
+	_, err = mustNew(t, &cli, kong.Resolvers(r)).Parse(nil)
+	require.NoError(t, err)
+	require.Equal(t, 8080, cli.Port)
+	require.Equal(t, "localhost", cli.Host)
+	require.True(t, cli.Verbose)
+	require.Equal(t, "", cli.Missing)
+}
+
+type countingBatchResolver struct {
+	values map[string]interface{}
+	calls  int
+	flags  []string
+}
+
+func (c *countingBatchResolver) Validate(app *kong.Application) error { return nil }
+func (c *countingBatchResolver) Resolve(context *kong.Context, parent *kong.Path, flag *kong.Flag) (interface{}, error) {
+	panic("Resolve() should not be called on a BatchResolver")
+}
+func (c *countingBatchResolver) ResolveAll(context *kong.Context, parent *kong.Path, flags []*kong.Flag) (map[*kong.Flag]interface{}, error) {
+	c.calls++
+	out := map[*kong.Flag]interface{}{}
+	for _, flag := range flags {
+		c.flags = append(c.flags, flag.Name)
+		if value, ok := c.values[flag.Name]; ok {
+			out[flag] = value
+		}
+	}
+	return out, nil
+}
+
+func TestBatchResolver(t *testing.T) {
+	var cli struct {
+		One   string
+		Two   int
+		Three string
+		Cmd   struct {
+			Four string
+		} `cmd:""`
+	}
+	resolver := &countingBatchResolver{values: map[string]interface{}{"one": "1", "two": 2, "three": "3", "four": "4"}}
+	_, err := mustNew(t, &cli, kong.Resolvers(resolver)).Parse([]string{"--three=three", "cmd"})
+	require.NoError(t, err)
+	require.Equal(t, "1", cli.One)
+	require.Equal(t, 2, cli.Two)
+	require.Equal(t, "three", cli.Three)
+	require.Equal(t, "4", cli.Cmd.Four)
+	// Once for the application's flags and once for the command's.
+	require.Equal(t, 2, resolver.calls)
+	require.Equal(t, []string{"help", "one", "two", "four"}, resolver.flags)
+}
+
+func TestBatchResolverError(t *testing.T) {
+	var cli struct {
+		Flag string
+	}
+	var resolver kong.ResolverFunc = func(context *kong.Context, parent *kong.Path, flag *kong.Flag) (interface{}, error) {
+		return nil, nil
+	}
+	failing := &failingBatchResolver{ResolverFunc: resolver}
+	_, err := mustNew(t, &cli, kong.Resolvers(failing)).Parse(nil)
+	require.EqualError(t, err, "unavailable")
+}
+
+type failingBatchResolver struct {
+	kong.ResolverFunc
+}
+
+func (f *failingBatchResolver) ResolveAll(context *kong.Context, parent *kong.Path, flags []*kong.Flag) (map[*kong.Flag]interface{}, error) {
+	return nil, errors.New("unavailable")
+}
@@ -43,6 +43,8 @@ func (t TokenType) String() string {
 type Token struct {
 	Value interface{}
 	Type  TokenType
+	// 1-based position of the command-line argument this token originated from, or 0 if unknown.
+	Position int
 }
 
 func (t Token) String() string {
@@ -111,35 +113,41 @@ func (t Token) IsValue() bool {
 //
 // 		[{FlagToken, "foo"}, {FlagValueToken, "bar"}]
 type Scanner struct {
-	args []Token
+	// Tokens in reverse order, so the front of the stream is the end of the slice and pushing and popping are
+	// amortised O(1).
+	stack []Token
 }
 
 // Scan creates a new Scanner from args with untyped tokens.
 func Scan(args ...string) *Scanner {
-	s := &Scanner{}
-	for _, arg := range args {
-		s.args = append(s.args, Token{Value: arg})
+	s := &Scanner{stack: make([]Token, len(args))}
+	for i, arg := range args {
+		s.stack[len(args)-1-i] = Token{Value: arg, Position: i + 1}
 	}
 	return s
 }
 
 // ScanFromTokens creates a new Scanner from a slice of tokens.
 func ScanFromTokens(tokens ...Token) *Scanner {
-	return &Scanner{args: tokens}
+	s := &Scanner{stack: make([]Token, len(tokens))}
+	for i, token := range tokens {
+		s.stack[len(tokens)-1-i] = token
+	}
+	return s
 }
 
 // Len returns the number of input arguments.
 func (s *Scanner) Len() int {
-	return len(s.args)
+	return len(s.stack)
 }
 
 // Pop the front token off the Scanner.
 func (s *Scanner) Pop() Token {
-	if len(s.args) == 0 {
+	if len(s.stack) == 0 {
 		return Token{Type: EOLToken}
 	}
-	arg := s.args[0]
-	s.args = s.args[1:]
+	arg := s.stack[len(s.stack)-1]
+	s.stack = s.stack[:len(s.stack)-1]
 	return arg
 }
 
@@ -192,10 +200,10 @@ func (s *Scanner) PopUntil(predicate func(Token) bool) (values []Token) {
 
 // Peek at the next Token or return an EOLToken.
 func (s *Scanner) Peek() Token {
-	if len(s.args) == 0 {
+	if len(s.stack) == 0 {
 		return Token{Type: EOLToken}
 	}
-	return s.args[0]
+	return s.stack[len(s.stack)-1]
 }
 
 // Push an untyped Token onto the front of the Scanner.
@@ -212,6 +220,15 @@ func (s *Scanner) PushTyped(arg interface{}, typ TokenType) *Scanner {
 
 // PushToken pushes a preconstructed Token onto the front of the Scanner.
 func (s *Scanner) PushToken(token Token) *Scanner {
-	s.args = append([]Token{token}, s.args...)
+	s.stack = append(s.stack, token)
 	return s
 }
+
+// Remaining tokens, front first.
+func (s *Scanner) tokens() []Token {
+	out := make([]Token, len(s.stack))
+	for i, token := range s.stack {
+		out[len(s.stack)-1-i] = token
+	}
+	return out
+}
@@ -26,3 +26,23 @@ func TestScannerPeek(t *testing.T) {
 	require.Equal(t, s.Pop().Value, "c")
 	require.Equal(t, s.Peek().Type, EOLToken)
 }
+
+func TestScannerPush(t *testing.T) {
+	s := Scan("c", "d")
+	s.Push("b").PushTyped("a", FlagToken)
+	require.Equal(t, 4, s.Len())
+	require.Equal(t, Token{Value: "a", Type: FlagToken}, s.Pop())
+	require.Equal(t, Token{Value: "b"}, s.Pop())
+	require.Equal(t, Token{Value: "c", Position: 1}, s.Pop())
+	s.PushToken(Token{Value: "e", Position: 3})
+	require.Equal(t, []Token{{Value: "e", Position: 3}, {Value: "d", Position: 2}}, s.tokens())
+}
+
+func TestScanFromTokens(t *testing.T) {
+	tokens := []Token{{Value: "a"}, {Value: "b", Type: PositionalArgumentToken}}
+	s := ScanFromTokens(tokens...)
+	require.Equal(t, tokens, s.tokens())
+	require.Equal(t, tokens[0], s.Pop())
+	require.Equal(t, tokens[1], s.Pop())
+	require.Equal(t, EOLToken, s.Pop().Type)
+}
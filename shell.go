@@ -0,0 +1,70 @@
+package kong
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Split s into arguments using shell quoting rules.
+//
+// Arguments are separated by whitespace. Single quotes preserve their contents literally, double quotes allow "\"
+// to escape '"', '\' and '$', and outside of quotes "\" escapes any character.
+func splitShellArgs(s string) ([]string, error) {
+	args := []string{}
+	arg := strings.Builder{}
+	inArg := false
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+
+		case r == '\\':
+			i++
+			if i >= len(runes) {
+				return nil, errors.New("trailing backslash")
+			}
+			arg.WriteRune(runes[i])
+			inArg = true
+
+		case r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != '\'' {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, errors.New("unterminated single quote")
+			}
+			arg.WriteString(string(runes[i+1 : end]))
+			i = end
+			inArg = true
+
+		case r == '"':
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune(`"\$`, runes[i+1]) {
+					i++
+				}
+				arg.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, errors.New("unterminated double quote")
+			}
+			inArg = true
+
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
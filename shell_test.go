@@ -0,0 +1,36 @@
+package kong
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitShellArgs(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+		err      string
+	}{
+		{input: "", expected: []string{}},
+		{input: "  --verbose   --region=eu ", expected: []string{"--verbose", "--region=eu"}},
+		{input: `--name 'hello world'`, expected: []string{"--name", "hello world"}},
+		{input: `--name "say \"hi\" \n"`, expected: []string{"--name", `say "hi" \n`}},
+		{input: `--name=a\ b ''`, expected: []string{"--name=a b", ""}},
+		{input: `--name 'unterminated`, err: "unterminated single quote"},
+		{input: `--name "unterminated`, err: "unterminated double quote"},
+		{input: `--name \`, err: "trailing backslash"},
+	}
+	for _, test := range tests {
+		// nolint: scopelint
+		t.Run(test.input, func(t *testing.T) {
+			actual, err := splitShellArgs(test.input)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -14,6 +14,7 @@ type Tag struct {
 	Cmd         bool
 	Arg         bool
 	Required    bool
+	RequiredFor []string // Paths of commands for which the flag is required, eg. "server logs".
 	Optional    bool
 	Name        string
 	Help        string
@@ -22,7 +23,10 @@ type Tag struct {
 	Format      string
 	PlaceHolder string
 	Env         string
+	ConfigFiles []string // Configuration files loaded when a command is selected, applying only to its flags.
+	Config      string   // Dot-separated path of the flag's key in configuration files, overriding the resolver's KeyStrategy.
 	Short       rune
+	ShortOnly   bool
 	Hidden      bool
 	Sep         rune
 	MapSep      rune
@@ -32,6 +36,13 @@ type Tag struct {
 	Vars        Vars
 	Prefix      string // Optional prefix on anonymous structs. All sub-flags will have this prefix.
 	Embed       bool
+	BoolValues  []BoolVocabulary // Words accepted for boolean values, overriding BoolValues.
+	Quoted      bool             // Use CSV-style quoting rather than escaping when splitting slices and maps.
+	Flag        bool             // Positional argument may also be set with a flag.
+	Arity       int              // Number of values consumed by a flag.
+	NoCLI       bool             // Value may not be set on the command-line.
+	NoEnv       bool             // Value may not be set from its environment variable or EnvArgs().
+	NoConfig    bool             // Value may not be set by resolvers, such as configuration files.
 
 	// Storage for all tag keys for arbitrary lookups.
 	items map[string][]string
@@ -130,6 +141,13 @@ func parseTag(fv reflect.Value, ft reflect.StructField) *Tag {
 	t := &Tag{
 		items: parseTagItems(getTagInfo(ft)),
 	}
+	t.hydrate(fv)
+	return t
+}
+
+// Set the parsed fields of the tag from its items.
+func (t *Tag) hydrate(fv reflect.Value) { // nolint: gocyclo
+	*t = Tag{items: t.items}
 	t.Cmd = t.Has("cmd")
 	t.Arg = t.Has("arg")
 	required := t.Has("required")
@@ -140,6 +158,16 @@ func parseTag(fv reflect.Value, ft reflect.StructField) *Tag {
 	t.Required = required
 	t.Optional = optional
 	t.Default = t.Get("default")
+	if t.Has("requiredfor") {
+		if t.Arg {
+			fail("requiredfor is not supported on positional arguments")
+		}
+		for _, path := range strings.Split(t.Get("requiredfor"), ",") {
+			if path = strings.Join(strings.Fields(path), " "); path != "" {
+				t.RequiredFor = append(t.RequiredFor, path)
+			}
+		}
+	}
 	// Arguments with defaults are always optional.
 	if t.Arg && t.Default != "" {
 		t.Optional = true
@@ -148,7 +176,25 @@ func parseTag(fv reflect.Value, ft reflect.StructField) *Tag {
 	t.Help = t.Get("help")
 	t.Type = t.Get("type")
 	t.Env = t.Get("env")
+	if t.Env != "" {
+		t.Env = t.Get("envprefix") + t.Env
+	}
+	t.Config = t.Get("config")
+	if t.Has("configfiles") {
+		if !t.Cmd {
+			fail("configfiles can only be used on commands")
+		}
+		for _, path := range strings.Split(t.Get("configfiles"), ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				t.ConfigFiles = append(t.ConfigFiles, path)
+			}
+		}
+	}
 	t.Short, _ = t.GetRune("short")
+	t.ShortOnly = t.Has("shortonly")
+	if t.ShortOnly && t.Short == 0 {
+		fail("shortonly requires a short flag")
+	}
 	t.Hidden = t.Has("hidden")
 	t.Format = t.Get("format")
 	t.Sep, _ = t.GetRune("sep")
@@ -157,6 +203,36 @@ func parseTag(fv reflect.Value, ft reflect.StructField) *Tag {
 	t.Xor = t.Get("xor")
 	t.Prefix = t.Get("prefix")
 	t.Embed = t.Has("embed")
+	t.Quoted = t.Has("quoted")
+	if t.Has("boolvalues") {
+		for _, pair := range strings.Split(t.Get("boolvalues"), ",") {
+			words := strings.Split(pair, "/")
+			if len(words) != 2 || words[0] == "" || words[1] == "" {
+				fail("boolvalues should be in the form true/false,... but got %q", t.Get("boolvalues"))
+			}
+			t.BoolValues = append(t.BoolValues, BoolVocabulary{True: words[0], False: words[1]})
+		}
+	}
+	t.Flag = t.Has("flag")
+	if t.Flag && !t.Arg {
+		fail("flag can only be used with arg")
+	}
+	if t.Has("arity") {
+		arity, err := strconv.Atoi(t.Get("arity"))
+		if err != nil || arity < 1 {
+			fail("invalid arity %q", t.Get("arity"))
+		}
+		if t.Arg {
+			fail("arity is not supported on positional arguments")
+		}
+		t.Arity = arity
+	}
+	t.NoCLI = t.Has("nocli")
+	t.NoEnv = t.Has("noenv")
+	t.NoConfig = t.Has("noconfig")
+	if t.Arg && (t.NoCLI || t.NoConfig) {
+		fail("nocli and noconfig are not supported on positional arguments")
+	}
 	if t.Sep == 0 {
 		if t.Get("sep") == "none" {
 			t.Sep = -1
@@ -184,7 +260,58 @@ func parseTag(fv reflect.Value, ft reflect.StructField) *Tag {
 		t.PlaceHolder = strings.ToUpper(dashedString(fv.Type().Name()))
 	}
 	t.Enum = t.Get("enum")
-	return t
+}
+
+// A TagInheritance defines how the value of a tag key on an embedded struct is inherited by the struct's fields.
+type TagInheritance int
+
+// Tag inheritance policies.
+const (
+	// InheritNone does not inherit the key.
+	InheritNone TagInheritance = iota
+	// InheritUnlessSet uses the embedded struct's value unless the field has the key, eg. group:"X".
+	InheritUnlessSet
+	// InheritPrefix prepends the embedded struct's value to the field's, eg. prefix:"X".
+	InheritPrefix
+	// InheritAppend adds the embedded struct's values before the field's, for keys that can occur multiple times,
+	// eg. set:"K=V".
+	InheritAppend
+)
+
+// Inheritance of builtin tag keys.
+//
+// "envprefix" is prepended to the "env" of fields, eg. envprefix:"DB_".
+var builtinTagInheritance = map[string]TagInheritance{
+	"group":       InheritUnlessSet,
+	"hidden":      InheritUnlessSet,
+	"placeholder": InheritUnlessSet,
+	"prefix":      InheritPrefix,
+	"envprefix":   InheritPrefix,
+	"set":         InheritAppend,
+}
+
+// Inherit keys from the tag of an embedded struct, according to the given policies.
+//
+// Note that there is no way to unset an inherited boolean key such as "hidden" on a field, as eg. hidden:"false" is
+// treated the same as hidden:"".
+func (t *Tag) inherit(parent *Tag, policies map[string]TagInheritance) {
+	for key, policy := range policies {
+		values, ok := parent.items[key]
+		if !ok {
+			continue
+		}
+		switch policy {
+		case InheritUnlessSet:
+			if !t.Has(key) {
+				t.items[key] = values
+			}
+		case InheritPrefix:
+			t.items[key] = []string{parent.Get(key) + t.Get(key)}
+		case InheritAppend:
+			t.items[key] = append(append([]string{}, values...), t.items[key]...)
+		case InheritNone:
+		}
+	}
 }
 
 // Has returns true if the tag contained the given key.
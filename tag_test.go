@@ -125,6 +125,126 @@ func TestTagSetOnEmbeddedStruct(t *testing.T) {
 	require.Contains(t, buf.String(), `A key from somewhere.`)
 }
 
+func TestTagInheritance(t *testing.T) {
+	type Database struct {
+		Host string `env:"HOST"`
+		Port int    `env:"PORT" placeholder:"N"`
+		User string
+	}
+	type Debug struct {
+		Trace bool `hidden:""`
+	}
+	var cli struct {
+		Database `embed:"" prefix:"db-" envprefix:"APP_DB_" placeholder:"VALUE" group:"Database"`
+		Debug    `hidden:""`
+	}
+	p := mustNew(t, &cli)
+	flags := map[string]*kong.Flag{}
+	for _, flag := range p.Model.Flags {
+		flags[flag.Name] = flag
+	}
+	require.Equal(t, "APP_DB_HOST", flags["db-host"].Env)
+	require.Equal(t, "APP_DB_PORT", flags["db-port"].Env)
+	require.Equal(t, "", flags["db-user"].Env)
+	require.Equal(t, "VALUE", flags["db-host"].PlaceHolder)
+	require.Equal(t, "N", flags["db-port"].PlaceHolder)
+	require.Equal(t, "Database", flags["db-user"].Group)
+	require.True(t, flags["trace"].Hidden)
+
+	defer tempEnv(envMap{"APP_DB_HOST": "db.example.com"})()
+	_, err := p.Parse(nil)
+	require.NoError(t, err)
+	require.Equal(t, "db.example.com", cli.Host)
+}
+
+func TestPlaceholderInheritedByValueFlagsOnly(t *testing.T) {
+	type Options struct {
+		Host    string
+		Verbose bool
+		Version kong.VersionFlag
+		Sub     struct{} `cmd:""`
+	}
+	type Args struct {
+		Target string `arg:"" optional:""`
+	}
+	var cli struct {
+		Options `embed:"" placeholder:"VALUE"`
+	}
+	p := mustNew(t, &cli)
+	flags := map[string]*kong.Flag{}
+	for _, flag := range p.Model.Flags {
+		flags[flag.Name] = flag
+	}
+	require.Equal(t, "VALUE", flags["host"].PlaceHolder)
+	require.False(t, flags["verbose"].Tag.Has("placeholder"))
+	require.False(t, flags["version"].Tag.Has("placeholder"))
+	require.False(t, p.Model.Children[0].Tag.Has("placeholder"))
+
+	var args struct {
+		Args `embed:"" placeholder:"VALUE"`
+	}
+	p = mustNew(t, &args)
+	require.False(t, p.Model.Positional[0].Tag.Has("placeholder"))
+}
+
+func TestNestedTagInheritance(t *testing.T) {
+	type Inner struct {
+		Key string `env:"KEY"`
+	}
+	type Outer struct {
+		Inner `embed:"" prefix:"inner-" envprefix:"INNER_"`
+	}
+	var cli struct {
+		Outer `embed:"" prefix:"outer-" envprefix:"OUTER_"`
+	}
+	p := mustNew(t, &cli)
+	flag := p.Model.Flags[1]
+	require.Equal(t, "outer-inner-key", flag.Name)
+	require.Equal(t, "OUTER_INNER_KEY", flag.Env)
+}
+
+func TestInheritTags(t *testing.T) {
+	type Experimental struct {
+		Turbo bool
+		Warp  bool `feature:"warp-drive"`
+	}
+	var cli struct {
+		Experimental `feature:"experiments" set:"a=1" hidden:""`
+	}
+	p := mustNew(t, &cli,
+		kong.InheritTags(kong.InheritUnlessSet, "feature"),
+		kong.InheritTags(kong.InheritNone, "hidden"))
+	flags := p.Model.Flags[1:]
+	require.Equal(t, "experiments", flags[0].Tag.Get("feature"))
+	require.Equal(t, "warp-drive", flags[1].Tag.Get("feature"))
+	require.False(t, flags[0].Hidden)
+	require.Equal(t, kong.Vars{"a": "1"}, flags[0].Tag.Vars)
+}
+
+func TestInheritTagsThroughNestedEmbeds(t *testing.T) {
+	type Inner struct {
+		Turbo bool
+		Warp  bool `feature:"warp-drive"`
+	}
+	type Outer struct {
+		Inner   `deprecated:"v2"`
+		Shields bool
+	}
+	var cli struct {
+		Outer `feature:"experiments" deprecated:"v1"`
+	}
+	p := mustNew(t, &cli, kong.InheritTags(kong.InheritUnlessSet, "feature", "deprecated"))
+	flags := p.Model.Flags[1:]
+	require.Equal(t, "turbo", flags[0].Name)
+	require.Equal(t, "experiments", flags[0].Tag.Get("feature"))
+	require.Equal(t, "v2", flags[0].Tag.Get("deprecated"))
+	require.Equal(t, "warp-drive", flags[1].Tag.Get("feature"))
+	require.Equal(t, "v2", flags[1].Tag.Get("deprecated"))
+	require.Equal(t, "shields", flags[2].Name)
+	require.Equal(t, "experiments", flags[2].Tag.Get("feature"))
+	require.Equal(t, "v1", flags[2].Tag.Get("deprecated"))
+}
+
 func TestTagSetOnCommand(t *testing.T) {
 	type Command struct {
 		Key string `help:"A key from ${where}."`